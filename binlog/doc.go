@@ -0,0 +1,8 @@
+// Package binlog parses the MySQL/MariaDB binary log event stream well
+// enough to recover table schemas (TABLE_MAP_EVENT) and row mutations
+// (WRITE/UPDATE/DELETE_ROWS_EVENT_V2), materializing the former into a
+// schema.TableDef and decoding the latter with the same column.ParseColumn
+// dispatch the InnoDB record parsers use - so callers can cross-check a
+// decoded .ibd record against the last row image the binlog recorded for
+// the same row.
+package binlog
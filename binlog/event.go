@@ -0,0 +1,65 @@
+// event.go - Binlog event header and the common Event interface
+package binlog
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// EventHeaderSize is the fixed size of the standard (v4) binlog event
+// header: timestamp(4) | type(1) | server_id(4) | event_size(4) |
+// log_pos(4) | flags(2).
+const EventHeaderSize = 19
+
+// EventType identifies a binlog event's kind. Only the subset this package
+// understands is named; everything else decodes to an UnknownEvent.
+type EventType uint8
+
+const (
+	EventTypeQuery             EventType = 2
+	EventTypeRotate            EventType = 4
+	EventTypeFormatDescription EventType = 15
+	EventTypeTableMap          EventType = 19
+	EventTypeWriteRowsV2       EventType = 30
+	EventTypeUpdateRowsV2      EventType = 31
+	EventTypeDeleteRowsV2      EventType = 32
+)
+
+// EventHeader is the 19-byte header common to every binlog event.
+type EventHeader struct {
+	Timestamp uint32
+	EventType EventType
+	ServerID  uint32
+	EventSize uint32
+	LogPos    uint32
+	Flags     uint16
+}
+
+// ParseEventHeader decodes the 19-byte header at the start of buf.
+func ParseEventHeader(buf []byte) (EventHeader, error) {
+	if len(buf) < EventHeaderSize {
+		return EventHeader{}, fmt.Errorf("short binlog event header: %d bytes", len(buf))
+	}
+	return EventHeader{
+		Timestamp: binary.LittleEndian.Uint32(buf[0:4]),
+		EventType: EventType(buf[4]),
+		ServerID:  binary.LittleEndian.Uint32(buf[5:9]),
+		EventSize: binary.LittleEndian.Uint32(buf[9:13]),
+		LogPos:    binary.LittleEndian.Uint32(buf[13:17]),
+		Flags:     binary.LittleEndian.Uint16(buf[17:19]),
+	}, nil
+}
+
+// Event is implemented by every decoded binlog event.
+type Event interface {
+	Header() EventHeader
+}
+
+// UnknownEvent is returned for event types this package doesn't decode
+// further; Body holds the bytes that follow the header, unparsed.
+type UnknownEvent struct {
+	Hdr  EventHeader
+	Body []byte
+}
+
+func (e *UnknownEvent) Header() EventHeader { return e.Hdr }
@@ -0,0 +1,276 @@
+// table_map.go - TABLE_MAP_EVENT, materialized into a schema.TableDef
+package binlog
+
+import (
+	"fmt"
+
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// MySQL column type codes (binlog_event.h's enum_field_types), the subset
+// that can appear in a TABLE_MAP_EVENT's column_types array.
+const (
+	mysqlTypeDecimal    = 0
+	mysqlTypeTiny       = 1
+	mysqlTypeShort      = 2
+	mysqlTypeLong       = 3
+	mysqlTypeFloat      = 4
+	mysqlTypeDouble     = 5
+	mysqlTypeNull       = 6
+	mysqlTypeTimestamp  = 7
+	mysqlTypeLongLong   = 8
+	mysqlTypeInt24      = 9
+	mysqlTypeDate       = 10
+	mysqlTypeTime       = 11
+	mysqlTypeDateTime   = 12
+	mysqlTypeYear       = 13
+	mysqlTypeNewDate    = 14
+	mysqlTypeVarchar    = 15
+	mysqlTypeBit        = 16
+	mysqlTypeTimestamp2 = 17
+	mysqlTypeDateTime2  = 18
+	mysqlTypeTime2      = 19
+	mysqlTypeJSON       = 245
+	mysqlTypeNewDecimal = 246
+	mysqlTypeEnum       = 247
+	mysqlTypeSet        = 248
+	mysqlTypeTinyBlob   = 249
+	mysqlTypeMediumBlob = 250
+	mysqlTypeLongBlob   = 251
+	mysqlTypeBlob       = 252
+	mysqlTypeVarString  = 253
+	mysqlTypeString     = 254
+	mysqlTypeGeometry   = 255
+)
+
+// ColumnDef is one column of a TABLE_MAP_EVENT, before it's been
+// materialized into a schema.Column.
+type ColumnDef struct {
+	Type     byte
+	Meta     []byte // the type's metadata bytes, see metadataSize
+	Nullable bool
+}
+
+// TableMapEvent names the table a following ROWS_EVENT mutates and
+// describes its columns' on-the-wire types.
+type TableMapEvent struct {
+	Hdr        EventHeader
+	TableID    uint64
+	Flags      uint16
+	SchemaName string
+	TableName  string
+	Columns    []ColumnDef
+}
+
+func (e *TableMapEvent) Header() EventHeader { return e.Hdr }
+
+func parseTableMapEvent(hdr EventHeader, body []byte) (*TableMapEvent, error) {
+	if len(body) < 8+2+1 {
+		return nil, fmt.Errorf("short TABLE_MAP_EVENT body")
+	}
+	pos := 0
+	tableID := readTableID(body[pos:])
+	pos += 6
+	flags := uint16(body[pos]) | uint16(body[pos+1])<<8
+	pos += 2
+
+	schemaLen := int(body[pos])
+	pos++
+	if pos+schemaLen+1 > len(body) {
+		return nil, fmt.Errorf("short TABLE_MAP_EVENT schema name")
+	}
+	schemaName := string(body[pos : pos+schemaLen])
+	pos += schemaLen + 1 // + NUL terminator
+
+	tableLen := int(body[pos])
+	pos++
+	if pos+tableLen+1 > len(body) {
+		return nil, fmt.Errorf("short TABLE_MAP_EVENT table name")
+	}
+	tableName := string(body[pos : pos+tableLen])
+	pos += tableLen + 1 // + NUL terminator
+
+	numCols, n, err := readPackedInt(body[pos:])
+	if err != nil {
+		return nil, fmt.Errorf("table column count: %w", err)
+	}
+	pos += n
+
+	if pos+int(numCols) > len(body) {
+		return nil, fmt.Errorf("short TABLE_MAP_EVENT column_types")
+	}
+	colTypes := body[pos : pos+int(numCols)]
+	pos += int(numCols)
+
+	metaLen, n, err := readPackedInt(body[pos:])
+	if err != nil {
+		return nil, fmt.Errorf("table metadata block length: %w", err)
+	}
+	pos += n
+	if pos+int(metaLen) > len(body) {
+		return nil, fmt.Errorf("short TABLE_MAP_EVENT metadata block")
+	}
+	metaBlock := body[pos : pos+int(metaLen)]
+	pos += int(metaLen)
+
+	columns := make([]ColumnDef, numCols)
+	metaPos := 0
+	for i := range columns {
+		typ := colTypes[i]
+		size := metadataSize(typ)
+		if metaPos+size > len(metaBlock) {
+			return nil, fmt.Errorf("short metadata for column %d (type %d)", i, typ)
+		}
+		columns[i] = ColumnDef{Type: typ, Meta: metaBlock[metaPos : metaPos+size]}
+		metaPos += size
+	}
+
+	// NULL bitmap: one bit per column, in column order, ceil(numCols/8) bytes.
+	nullBitmapLen := (int(numCols) + 7) / 8
+	if pos+nullBitmapLen > len(body) {
+		return nil, fmt.Errorf("short TABLE_MAP_EVENT NULL bitmap")
+	}
+	for i := range columns {
+		byteIdx, bitIdx := i/8, i%8
+		columns[i].Nullable = body[pos+byteIdx]&(1<<uint(bitIdx)) != 0
+	}
+
+	return &TableMapEvent{
+		Hdr:        hdr,
+		TableID:    tableID,
+		Flags:      flags,
+		SchemaName: schemaName,
+		TableName:  tableName,
+		Columns:    columns,
+	}, nil
+}
+
+// readTableID decodes the table_id's 6-byte little-endian encoding used
+// throughout the rows-event family.
+func readTableID(b []byte) uint64 {
+	var v uint64
+	for i := 5; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+// metadataSize returns how many bytes of the metadata block a column of
+// the given wire type consumes. Mirrors MySQL's
+// Table_map_log_event::save_field_metadata / Field::save_field_metadata.
+func metadataSize(typ byte) int {
+	switch typ {
+	case mysqlTypeFloat, mysqlTypeDouble,
+		mysqlTypeTimestamp2, mysqlTypeDateTime2, mysqlTypeTime2,
+		mysqlTypeBlob, mysqlTypeGeometry:
+		return 1
+	case mysqlTypeVarchar, mysqlTypeBit,
+		mysqlTypeJSON, mysqlTypeNewDecimal,
+		mysqlTypeEnum, mysqlTypeSet,
+		mysqlTypeVarString, mysqlTypeString:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ToTableDef materializes this TABLE_MAP_EVENT into a schema.TableDef,
+// mapping each wire type code to the closest schema.ColumnType. Columns
+// are named "col0", "col1", ... since TABLE_MAP_EVENT carries no column
+// names (those live in the server's data dictionary, not the binlog);
+// callers that know the real names can rename via TableDef.ColumnMap.
+func (e *TableMapEvent) ToTableDef() (*schema.TableDef, error) {
+	cols, err := e.schemaColumns()
+	if err != nil {
+		return nil, err
+	}
+	td := schema.NewTableDef(e.TableName)
+	for _, col := range cols {
+		if err := td.AddColumn(col); err != nil {
+			return nil, err
+		}
+	}
+	return td, nil
+}
+
+// schemaColumns materializes every ColumnDef into a schema.Column, in
+// column order. Shared by ToTableDef and RowsEvent.Decode, which needs the
+// same per-column schema.Column values to drive column.ParseColumn.
+func (e *TableMapEvent) schemaColumns() ([]*schema.Column, error) {
+	cols := make([]*schema.Column, len(e.Columns))
+	for i, c := range e.Columns {
+		col, err := columnDefToSchema(i, c)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", i, err)
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+func columnDefToSchema(ordinal int, c ColumnDef) (*schema.Column, error) {
+	col := &schema.Column{
+		Name:     fmt.Sprintf("col%d", ordinal),
+		Nullable: c.Nullable,
+	}
+
+	switch c.Type {
+	case mysqlTypeTiny:
+		col.Type = schema.TypeTinyInt
+	case mysqlTypeShort:
+		col.Type = schema.TypeSmallInt
+	case mysqlTypeInt24:
+		col.Type = schema.TypeMediumInt
+	case mysqlTypeLong:
+		col.Type = schema.TypeInt
+	case mysqlTypeLongLong:
+		col.Type = schema.TypeBigInt
+	case mysqlTypeFloat:
+		col.Type = schema.TypeFloat
+	case mysqlTypeDouble:
+		col.Type = schema.TypeDouble
+	case mysqlTypeNewDecimal, mysqlTypeDecimal:
+		col.Type = schema.TypeDecimal
+		if len(c.Meta) == 2 {
+			col.Precision = int(c.Meta[0])
+			col.Scale = int(c.Meta[1])
+		}
+	case mysqlTypeDate, mysqlTypeNewDate:
+		col.Type = schema.TypeDate
+	case mysqlTypeTime, mysqlTypeTime2:
+		col.Type = schema.TypeTime
+	case mysqlTypeDateTime, mysqlTypeDateTime2:
+		col.Type = schema.TypeDateTime
+	case mysqlTypeTimestamp, mysqlTypeTimestamp2:
+		col.Type = schema.TypeTimestamp
+	case mysqlTypeYear:
+		col.Type = schema.TypeYear
+	case mysqlTypeVarchar, mysqlTypeVarString:
+		col.Type = schema.TypeVarchar
+		if len(c.Meta) == 2 {
+			col.Length = int(c.Meta[0]) | int(c.Meta[1])<<8
+		}
+	case mysqlTypeString:
+		col.Type = schema.TypeChar
+		if len(c.Meta) == 2 {
+			col.Length = int(c.Meta[1])
+		}
+	case mysqlTypeBlob, mysqlTypeTinyBlob, mysqlTypeMediumBlob, mysqlTypeLongBlob:
+		col.Type = schema.TypeBlob
+	case mysqlTypeJSON:
+		col.Type = schema.TypeJSON
+	case mysqlTypeBit:
+		col.Type = schema.TypeBit
+		if len(c.Meta) == 2 {
+			col.Length = int(c.Meta[0]) + int(c.Meta[1])*8
+		}
+	case mysqlTypeEnum:
+		col.Type = schema.TypeEnum
+	case mysqlTypeSet:
+		col.Type = schema.TypeSet
+	default:
+		return nil, fmt.Errorf("unsupported binlog column type %d", c.Type)
+	}
+
+	return col, nil
+}
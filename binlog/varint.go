@@ -0,0 +1,40 @@
+// varint.go - MySQL's length-encoded ("packed") integer, as used for
+// binlog table-map column counts and rows-event column counts
+package binlog
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// readPackedInt decodes one length-encoded integer starting at buf[0]:
+// < 0xfb encodes the value directly in that one byte, 0xfc/0xfd/0xfe
+// introduce a 2/3/8-byte little-endian value, and 0xfb means NULL (not
+// valid in the contexts this package uses packed integers, so it errors).
+// Returns the value and the number of bytes consumed.
+func readPackedInt(buf []byte) (uint64, int, error) {
+	if len(buf) == 0 {
+		return 0, 0, fmt.Errorf("short read for packed integer")
+	}
+	switch b := buf[0]; {
+	case b < 0xfb:
+		return uint64(b), 1, nil
+	case b == 0xfc:
+		if len(buf) < 3 {
+			return 0, 0, fmt.Errorf("short read for 2-byte packed integer")
+		}
+		return uint64(binary.LittleEndian.Uint16(buf[1:3])), 3, nil
+	case b == 0xfd:
+		if len(buf) < 4 {
+			return 0, 0, fmt.Errorf("short read for 3-byte packed integer")
+		}
+		return uint64(buf[1]) | uint64(buf[2])<<8 | uint64(buf[3])<<16, 4, nil
+	case b == 0xfe:
+		if len(buf) < 9 {
+			return 0, 0, fmt.Errorf("short read for 8-byte packed integer")
+		}
+		return binary.LittleEndian.Uint64(buf[1:9]), 9, nil
+	default: // 0xfb
+		return 0, 0, fmt.Errorf("unexpected NULL packed integer")
+	}
+}
@@ -0,0 +1,244 @@
+// bufferpool.go - Sharded LRU page cache in front of a PageReader
+package goinnodb
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultShardCount is used when NewBufferPool isn't given one explicitly.
+// Splitting the cache across shards keeps concurrent readers of different
+// pages from serializing on a single mutex.
+const defaultShardCount = 16
+
+// BufferPoolOption configures a BufferPool at construction time.
+type BufferPoolOption func(*BufferPool)
+
+// WithShardCount overrides the number of LRU shards. Must be > 0.
+func WithShardCount(n int) BufferPoolOption {
+	return func(bp *BufferPool) {
+		if n > 0 {
+			bp.shardCount = n
+		}
+	}
+}
+
+// PageFetcher is the read side of a PageReader: the minimal interface
+// WalkTree needs, so it can be driven by either a plain PageReader or a
+// CachedPageReader sitting in front of a BufferPool.
+type PageFetcher interface {
+	ReadPage(pageNo uint32) (*InnerPage, error)
+}
+
+// BufferPool caches pages read through a PageReader in a sharded LRU, so
+// repeated reads of hot pages (root/branch INDEX pages, the data dictionary)
+// skip the underlying io.ReaderAt entirely. Concurrent cache misses for the
+// same page are coalesced into a single underlying read.
+type BufferPool struct {
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	reader     *PageReader
+	shardCount int
+	shards     []*bufferShard
+}
+
+type bufferShard struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List // front = most recently used
+	items    map[uint32]*list.Element
+	pins     map[uint32]int
+	inflight map[uint32]*pageCall
+}
+
+type bufferEntry struct {
+	pageNo uint32
+	page   *InnerPage
+}
+
+// pageCall is the in-flight state concurrent ReadPage callers for the same
+// missed page wait on, so only one of them actually reads through.
+type pageCall struct {
+	done chan struct{}
+	page *InnerPage
+	err  error
+}
+
+// NewBufferPool wraps reader with a sharded LRU cache holding up to
+// capacity pages in total, spread evenly across shards.
+func NewBufferPool(reader *PageReader, capacity int, opts ...BufferPoolOption) *BufferPool {
+	bp := &BufferPool{reader: reader, shardCount: defaultShardCount}
+	for _, opt := range opts {
+		opt(bp)
+	}
+	if bp.shardCount > capacity && capacity > 0 {
+		bp.shardCount = capacity
+	}
+	if bp.shardCount <= 0 {
+		bp.shardCount = 1
+	}
+
+	perShard := capacity / bp.shardCount
+	if perShard < 1 {
+		perShard = 1
+	}
+	bp.shards = make([]*bufferShard, bp.shardCount)
+	for i := range bp.shards {
+		bp.shards[i] = &bufferShard{
+			capacity: perShard,
+			ll:       list.New(),
+			items:    make(map[uint32]*list.Element),
+			pins:     make(map[uint32]int),
+			inflight: make(map[uint32]*pageCall),
+		}
+	}
+	return bp
+}
+
+func (bp *BufferPool) shardFor(pageNo uint32) *bufferShard {
+	return bp.shards[int(pageNo)%len(bp.shards)]
+}
+
+// ReadPage returns pageNo from cache if present, otherwise reads it through
+// the underlying PageReader and caches the result, evicting the shard's
+// least-recently-used unpinned page if it's now over capacity. Concurrent
+// misses for the same page share one underlying read rather than each
+// issuing their own.
+func (bp *BufferPool) ReadPage(pageNo uint32) (*InnerPage, error) {
+	shard := bp.shardFor(pageNo)
+
+	shard.mu.Lock()
+	if el, ok := shard.items[pageNo]; ok {
+		shard.ll.MoveToFront(el)
+		page := el.Value.(*bufferEntry).page
+		shard.mu.Unlock()
+		atomic.AddUint64(&bp.hits, 1)
+		return page, nil
+	}
+	if call, ok := shard.inflight[pageNo]; ok {
+		shard.mu.Unlock()
+		<-call.done
+		return call.page, call.err
+	}
+
+	call := &pageCall{done: make(chan struct{})}
+	shard.inflight[pageNo] = call
+	shard.mu.Unlock()
+
+	atomic.AddUint64(&bp.misses, 1)
+	page, err := bp.reader.ReadPage(pageNo)
+	call.page, call.err = page, err
+	close(call.done)
+
+	shard.mu.Lock()
+	delete(shard.inflight, pageNo)
+	if err == nil {
+		el := shard.ll.PushFront(&bufferEntry{pageNo: pageNo, page: page})
+		shard.items[pageNo] = el
+		bp.evictLocked(shard)
+	}
+	shard.mu.Unlock()
+
+	return page, err
+}
+
+// evictLocked drops least-recently-used pages until the shard is back at
+// capacity, skipping over any page with a positive pin count. Called with
+// shard.mu held.
+func (bp *BufferPool) evictLocked(shard *bufferShard) {
+	for shard.ll.Len() > shard.capacity {
+		el := shard.ll.Back()
+		for el != nil && shard.pins[el.Value.(*bufferEntry).pageNo] > 0 {
+			el = el.Prev()
+		}
+		if el == nil {
+			// Every cached page in this shard is pinned; leave it over
+			// capacity rather than evict something still in use.
+			break
+		}
+		shard.ll.Remove(el)
+		delete(shard.items, el.Value.(*bufferEntry).pageNo)
+		atomic.AddUint64(&bp.evictions, 1)
+	}
+}
+
+// Pin marks pageNo as in use, so evictLocked won't drop it even if it
+// becomes the shard's least-recently-used entry. Pins nest: a page pinned
+// twice needs two Unpin calls before it's eligible for eviction again.
+func (bp *BufferPool) Pin(pageNo uint32) {
+	shard := bp.shardFor(pageNo)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	shard.pins[pageNo]++
+}
+
+// Unpin releases one Pin on pageNo.
+func (bp *BufferPool) Unpin(pageNo uint32) {
+	shard := bp.shardFor(pageNo)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if shard.pins[pageNo] <= 1 {
+		delete(shard.pins, pageNo)
+		return
+	}
+	shard.pins[pageNo]--
+}
+
+// Evict removes pageNo from the cache, if present, regardless of pin state.
+func (bp *BufferPool) Evict(pageNo uint32) {
+	shard := bp.shardFor(pageNo)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if el, ok := shard.items[pageNo]; ok {
+		shard.ll.Remove(el)
+		delete(shard.items, pageNo)
+	}
+}
+
+// Len returns the total number of pages currently cached across all shards.
+func (bp *BufferPool) Len() int {
+	total := 0
+	for _, shard := range bp.shards {
+		shard.mu.Lock()
+		total += shard.ll.Len()
+		shard.mu.Unlock()
+	}
+	return total
+}
+
+// BufferPoolStats is a point-in-time snapshot of a BufferPool's cumulative
+// hit/miss/eviction counts.
+type BufferPoolStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// Stats returns the BufferPool's cumulative hit/miss/eviction counters.
+func (bp *BufferPool) Stats() BufferPoolStats {
+	return BufferPoolStats{
+		Hits:      atomic.LoadUint64(&bp.hits),
+		Misses:    atomic.LoadUint64(&bp.misses),
+		Evictions: atomic.LoadUint64(&bp.evictions),
+	}
+}
+
+// CachedPageReader adapts a BufferPool to the PageFetcher interface, so
+// code written against a PageReader (WalkTree, in particular) can be
+// handed a cached reader instead without knowing the difference.
+type CachedPageReader struct {
+	pool *BufferPool
+}
+
+// NewCachedPageReader wraps pool as a PageFetcher.
+func NewCachedPageReader(pool *BufferPool) *CachedPageReader {
+	return &CachedPageReader{pool: pool}
+}
+
+// ReadPage implements PageFetcher by delegating to the underlying BufferPool.
+func (r *CachedPageReader) ReadPage(pageNo uint32) (*InnerPage, error) {
+	return r.pool.ReadPage(pageNo)
+}
@@ -0,0 +1,132 @@
+package goinnodb
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// validTestPage returns a minimal but structurally valid 16KiB page: a FIL
+// header/trailer pair whose low32 LSNs agree, which is all NewInnerPage
+// checks when ChecksumModeAny (the default) is in effect.
+func validTestPage() []byte {
+	p := make([]byte, PageSize)
+	putBe32(p, 8, filNull)  // prev
+	putBe32(p, 12, filNull) // next
+	return p
+}
+
+// blockingReaderAt serves validTestPage on every ReadAt, counting calls and
+// blocking each one on release until it's closed - standing in for a slow
+// underlying .ibd read so concurrent BufferPool.ReadPage callers for the
+// same page have to wait behind the single in-flight one instead of each
+// reaching the reader themselves.
+type blockingReaderAt struct {
+	mu      sync.Mutex
+	calls   int
+	release chan struct{}
+}
+
+func (r *blockingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	r.calls++
+	r.mu.Unlock()
+	<-r.release
+	copy(p, validTestPage())
+	return len(p), nil
+}
+
+func TestBufferPool_ConcurrentReadPage_SingleFlight(t *testing.T) {
+	r := &blockingReaderAt{release: make(chan struct{})}
+	bp := NewBufferPool(NewPageReader(r), 16)
+
+	const n = 20
+	results := make([]*InnerPage, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = bp.ReadPage(7)
+		}(i)
+	}
+
+	// Give every goroutine a chance to either become the single in-flight
+	// reader or queue up behind it before the read is allowed to complete.
+	time.Sleep(50 * time.Millisecond)
+	close(r.release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("goroutine %d: ReadPage: %v", i, err)
+		}
+		if results[i] != results[0] {
+			t.Fatalf("goroutine %d got a different *InnerPage than goroutine 0; coalesced reads should share one result", i)
+		}
+	}
+
+	r.mu.Lock()
+	calls := r.calls
+	r.mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 underlying read for %d concurrent callers, got %d", n, calls)
+	}
+
+	stats := bp.Stats()
+	if stats.Misses != 1 {
+		t.Fatalf("expected 1 miss, got %d", stats.Misses)
+	}
+}
+
+// staticReaderAt serves validTestPage for any offset, with no blocking.
+type staticReaderAt struct{}
+
+func (staticReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	copy(p, validTestPage())
+	return len(p), nil
+}
+
+func TestBufferPool_PinSurvivesEvictionPressure(t *testing.T) {
+	bp := NewBufferPool(NewPageReader(staticReaderAt{}), 2, WithShardCount(1))
+
+	if _, err := bp.ReadPage(1); err != nil {
+		t.Fatalf("ReadPage(1): %v", err)
+	}
+	bp.Pin(1)
+	defer bp.Unpin(1)
+
+	if _, err := bp.ReadPage(2); err != nil {
+		t.Fatalf("ReadPage(2): %v", err)
+	}
+	if _, err := bp.ReadPage(3); err != nil {
+		t.Fatalf("ReadPage(3): %v", err)
+	}
+
+	if got := bp.Len(); got != 2 {
+		t.Fatalf("expected shard back at capacity 2, got %d cached pages", got)
+	}
+
+	before := bp.Stats()
+	if _, err := bp.ReadPage(1); err != nil {
+		t.Fatalf("ReadPage(1) re-read: %v", err)
+	}
+	after := bp.Stats()
+	if after.Misses != before.Misses {
+		t.Fatal("pinned page 1 was evicted despite being pinned")
+	}
+	if after.Hits != before.Hits+1 {
+		t.Fatal("expected re-reading page 1 to be a cache hit")
+	}
+
+	before = bp.Stats()
+	if _, err := bp.ReadPage(2); err != nil {
+		t.Fatalf("ReadPage(2) re-read: %v", err)
+	}
+	after = bp.Stats()
+	if after.Misses != before.Misses+1 {
+		t.Fatal("expected page 2 (unpinned, least recently used) to have been evicted")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"strings"
 	"text/tabwriter"
 
 	goinnodb "github.com/wilhasse/go-innodb"
@@ -23,6 +22,9 @@ func main() {
 		verbose   = flag.Bool("v", false, "Verbose output")
 		sqlFile   = flag.String("sql", "", "Path to SQL file with CREATE TABLE statement")
 		parseData = flag.Bool("parse", false, "Parse column data using table schema")
+		recFormat = flag.String("record-format", "auto", "Record data format in verbose output: auto, hex, ascii-encoded, or redacted")
+		treeRoot  = flag.Int("tree", -1, "Walk the B+tree rooted at this page number and print its structure, instead of reading a single page")
+		cacheSize = flag.Int("tree-cache", 0, "Cache up to this many pages while walking -tree (0 disables caching)")
 	)
 
 	flag.Usage = func() {
@@ -34,6 +36,9 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s -file data.ibd -page 3\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -file data.ibd -page 3 -format json\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -file data.ibd -page 3 -records\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -file data.ibd -page 3 -records -v -record-format hex\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -file data.ibd -tree 3\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -file data.ibd -tree 3 -tree-cache 256\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -68,6 +73,11 @@ func main() {
 	// Create page reader
 	reader := goinnodb.NewPageReader(f)
 
+	if *treeRoot >= 0 {
+		outputTree(reader, uint32(*treeRoot), *maxRecs, *cacheSize)
+		return
+	}
+
 	// Read the page
 	page, err := reader.ReadPage(uint32(*pageNum))
 	if err != nil {
@@ -82,11 +92,11 @@ func main() {
 	case "summary":
 		outputSummary(page)
 	default:
-		outputText(page, *showRecs, *maxRecs, *verbose, tableDef, *parseData)
+		outputText(page, *showRecs, *maxRecs, *verbose, tableDef, *parseData, *recFormat)
 	}
 }
 
-func outputText(page *goinnodb.InnerPage, showRecs bool, maxRecs int, verbose bool, tableDef *schema.TableDef, parseData bool) {
+func outputText(page *goinnodb.InnerPage, showRecs bool, maxRecs int, verbose bool, tableDef *schema.TableDef, parseData bool, recFormat string) {
 	fmt.Printf("=== Page %d ===\n", page.PageNo)
 	fmt.Printf("\nFIL Header:\n")
 	fmt.Printf("  Checksum:    0x%08x\n", page.FIL.Checksum)
@@ -150,27 +160,30 @@ func outputText(page *goinnodb.InnerPage, showRecs bool, maxRecs int, verbose bo
 				parsedRecords := make([]goinnodb.GenericRecord, 0)
 
 				// Walk and parse each record
-				rawRecords, err := goinnodb.WalkRecords(indexPage, maxRecs, true)
+				rawRecords, err := indexPage.WalkRecords(maxRecs, true)
 				if err != nil {
 					fmt.Printf("  Error walking records: %v\n", err)
 				} else {
 					for _, rawRec := range rawRecords {
-						// Re-parse with column data
+						// Re-parse with column data. parser.ParseRecord returns a
+						// record.GenericRecord (schema-aware), distinct from this
+						// file's goinnodb.GenericRecord; keep rawRec's header and
+						// position and just graft on the parsed Data/Values.
 						parsedRec, err := parser.ParseRecord(indexPage.Inner.Data, rawRec.PrimaryKeyPos, indexPage.IsLeaf())
 						if err != nil {
 							// Fall back to raw record if parsing fails
 							parsedRecords = append(parsedRecords, rawRec)
 						} else {
-							// Copy metadata from raw record
-							parsedRec.PageNumber = rawRec.PageNumber
-							parsedRecords = append(parsedRecords, *parsedRec)
+							rawRec.Data = parsedRec.Data
+							rawRec.Values = parsedRec.Values
+							parsedRecords = append(parsedRecords, rawRec)
 						}
 					}
 					records = parsedRecords
 				}
 			} else {
 				// Use standard walk without parsing
-				records, err = goinnodb.WalkRecords(indexPage, maxRecs, true)
+				records, err = indexPage.WalkRecords(maxRecs, true)
 				if err != nil {
 					fmt.Printf("  Error walking records: %v\n", err)
 				}
@@ -202,27 +215,25 @@ func outputText(page *goinnodb.InnerPage, showRecs bool, maxRecs int, verbose bo
 						fmt.Fprintln(w)
 					}
 				} else if verbose {
-					// Original verbose display with hex data
-					fmt.Fprintf(w, "  #\tHeap#\tType\tDeleted\tOwned\tNext\tData (hex)\tReadable Strings\n")
+					formatter, err := goinnodb.NewRecordFormatter(goinnodb.RecordFormat(recFormat))
+					if err != nil {
+						fmt.Printf("  Error: %v\n", err)
+						return
+					}
+
+					fmt.Fprintf(w, "  #\tHeap#\tType\tDeleted\tOwned\tNext\tData (%s)\n", recFormat)
 					for i, rec := range records {
-						dataHex := ""
-						readable := ""
+						data := ""
 						if len(rec.Data) > 0 {
-							if len(rec.Data) > 50 {
-								dataHex = fmt.Sprintf("%x... (%d bytes)", rec.Data[:50], len(rec.Data))
-							} else {
-								dataHex = fmt.Sprintf("%x", rec.Data)
-							}
-							readable = extractReadableStrings(rec.Data)
+							data = formatter.Format(rec.Data)
 						}
-						fmt.Fprintf(w, "  %d\t%d\t%s\t%v\t%d\t%d\t%s\t%s\n",
+						fmt.Fprintf(w, "  %d\t%d\t%s\t%v\t%d\t%d\t%s\n",
 							i, rec.Header.HeapNumber,
 							recordTypeName(rec.Header.Type),
 							rec.Header.FlagsDeleted,
 							rec.Header.NumOwned,
 							rec.Header.NextRecOffset,
-							dataHex,
-							readable)
+							data)
 					}
 				} else {
 					fmt.Fprintf(w, "  #\tHeap#\tType\tDeleted\tOwned\tNext\n")
@@ -245,6 +256,32 @@ func outputText(page *goinnodb.InnerPage, showRecs bool, maxRecs int, verbose bo
 	}
 }
 
+func outputTree(reader *goinnodb.PageReader, rootPageNo uint32, maxRecs int, cacheSize int) {
+	var fetcher goinnodb.PageFetcher = reader
+	var pool *goinnodb.BufferPool
+	if cacheSize > 0 {
+		pool = goinnodb.NewBufferPool(reader, cacheSize)
+		fetcher = goinnodb.NewCachedPageReader(pool)
+	}
+
+	root, err := goinnodb.WalkTree(fetcher, rootPageNo, maxRecs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error walking tree from page %d: %v\n", rootPageNo, err)
+		os.Exit(1)
+	}
+
+	goinnodb.PrintTree(os.Stdout, root)
+
+	stats := goinnodb.ComputeTreeStats(root)
+	fmt.Printf("\nStats: internal=%d leaf=%d records=%d max_depth=%d\n",
+		stats.InternalNodes, stats.LeafNodes, stats.TotalRecords, stats.MaxDepth)
+
+	if pool != nil {
+		bpStats := pool.Stats()
+		fmt.Printf("Cache: hits=%d misses=%d evictions=%d\n", bpStats.Hits, bpStats.Misses, bpStats.Evictions)
+	}
+}
+
 func outputSummary(page *goinnodb.InnerPage) {
 	fmt.Printf("Page %d: Type=%s, Space=%d, LSN=%d",
 		page.PageNo, pageTypeName(page.FIL.PageType),
@@ -300,7 +337,7 @@ func outputJSON(page *goinnodb.InnerPage, showRecs bool, maxRecs int, tableDef *
 			}
 
 			if showRecs {
-				if records, err := goinnodb.WalkRecords(indexPage, maxRecs, true); err == nil {
+				if records, err := indexPage.WalkRecords(maxRecs, true); err == nil {
 					recData := make([]map[string]interface{}, len(records))
 					for i, rec := range records {
 						recData[i] = map[string]interface{}{
@@ -381,8 +418,8 @@ func directionName(d goinnodb.PageDirection) string {
 		return "RIGHT"
 	case goinnodb.DirSamePage:
 		return "SAME_PAGE"
-	case goinnodb.DirDescending:
-		return "DESCENDING"
+	case goinnodb.DirSameRec:
+		return "SAME_REC"
 	case goinnodb.DirNoDirection:
 		return "NO_DIRECTION"
 	default:
@@ -402,29 +439,3 @@ func leafOrInternal(p *goinnodb.IndexPage) string {
 	}
 	return "(internal)"
 }
-
-// extractReadableStrings extracts ASCII strings from binary data
-func extractReadableStrings(data []byte) string {
-	var result []string
-	var current []byte
-
-	for _, b := range data {
-		// Check if byte is printable ASCII (32-126)
-		if b >= 32 && b <= 126 {
-			current = append(current, b)
-		} else {
-			// If we have accumulated at least 3 characters, consider it a string
-			if len(current) >= 3 {
-				result = append(result, string(current))
-			}
-			current = nil
-		}
-	}
-
-	// Don't forget the last string if any
-	if len(current) >= 3 {
-		result = append(result, string(current))
-	}
-
-	return strings.Join(result, " | ")
-}
@@ -0,0 +1,43 @@
+// bit_parser.go - Parser for the BIT column type
+package column
+
+import (
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// BitParser handles BIT(M) columns, stored as ceil(M/8) big-endian bytes.
+type BitParser struct {
+	BaseParser
+}
+
+// Parse decodes a BIT value. Columns up to 64 bits decode to a uint64;
+// wider columns decode to the raw big-endian byte slice.
+func (p *BitParser) Parse(input []byte, offset int, col *schema.Column, varLen int) (interface{}, int, error) {
+	if col.Type != schema.TypeBit {
+		return nil, 0, schema.ErrUnsupportedType
+	}
+
+	size := (col.Length + 7) / 8
+	raw, err := p.readBytes(input, offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if size > 8 {
+		return append([]byte{}, raw...), size, nil
+	}
+
+	var val uint64
+	for _, b := range raw {
+		val = val<<8 | uint64(b)
+	}
+	return val, size, nil
+}
+
+// Skip skips a BIT value without parsing it.
+func (p *BitParser) Skip(input []byte, offset int, col *schema.Column, varLen int) (int, error) {
+	if col.Type != schema.TypeBit {
+		return 0, schema.ErrUnsupportedType
+	}
+	return (col.Length + 7) / 8, nil
+}
@@ -0,0 +1,141 @@
+// decimal_parser.go - Parser for the DECIMAL/NUMERIC column types
+package column
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// dig2Bytes maps a count of leftover decimal digits (0-8) to how many bytes
+// MySQL's packed decimal format spends encoding them, the same table
+// decimal.cc's dig2bytes uses.
+var dig2Bytes = [9]int{0, 1, 1, 2, 2, 3, 3, 4, 4}
+
+// DecimalParser handles DECIMAL and NUMERIC columns, MySQL's big-endian
+// packed decimal binary format.
+type DecimalParser struct {
+	BaseParser
+}
+
+// Parse decodes a packed decimal value into its base-10 string form.
+func (p *DecimalParser) Parse(input []byte, offset int, col *schema.Column, varLen int) (interface{}, int, error) {
+	if col.Type != schema.TypeDecimal && col.Type != schema.TypeNumeric {
+		return nil, 0, schema.ErrUnsupportedType
+	}
+
+	size := col.StorageSize()
+	raw, err := p.readBytes(input, offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The sign lives in the top bit of the first byte: 1 = positive (the
+	// opposite of two's complement), 0 = negative. Flip it to read the
+	// magnitude normally, then XOR every byte when the value is negative,
+	// since negative magnitudes are stored ones'-complemented.
+	buf := append([]byte{}, raw...)
+	negative := buf[0]&0x80 == 0
+	buf[0] ^= 0x80
+	if negative {
+		for i := range buf {
+			buf[i] ^= 0xFF
+		}
+	}
+
+	intDigits := col.Precision - col.Scale
+	intStr, pos, err := decodeDecimalDigits(buf, 0, intDigits, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	fracStr, _, err := decodeDecimalDigits(buf, pos, col.Scale, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sb strings.Builder
+	if negative {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(intStr)
+	if col.Scale > 0 {
+		sb.WriteByte('.')
+		sb.WriteString(fracStr)
+	}
+	return sb.String(), size, nil
+}
+
+// decodeDecimalDigits reads digitCount base-10 digits starting at byte
+// offset pos in buf, in 9-digit (4-byte) groups plus one smaller group
+// (sized by dig2Bytes) for any leftover digits that don't divide evenly by
+// 9. The leftover group holds the most significant leftover digits, so for
+// the integer part (leadingLeftover) it comes first; for the fractional
+// part it comes last. Returns the digit string and the byte offset just
+// past what it read.
+func decodeDecimalDigits(buf []byte, pos, digitCount int, leadingLeftover bool) (string, int, error) {
+	if digitCount == 0 {
+		return "", pos, nil
+	}
+
+	leftover := digitCount % 9
+	fullGroups := digitCount / 9
+	var sb strings.Builder
+
+	readLeftover := func() error {
+		if leftover == 0 {
+			return nil
+		}
+		n := dig2Bytes[leftover]
+		if pos+n > len(buf) {
+			return fmt.Errorf("short read for decimal leftover group")
+		}
+		sb.WriteString(fmt.Sprintf("%0*d", leftover, decodeBigEndianUint(buf[pos:pos+n])))
+		pos += n
+		return nil
+	}
+	readFullGroups := func() error {
+		for i := 0; i < fullGroups; i++ {
+			if pos+4 > len(buf) {
+				return fmt.Errorf("short read for decimal group")
+			}
+			sb.WriteString(fmt.Sprintf("%09d", decodeBigEndianUint(buf[pos:pos+4])))
+			pos += 4
+		}
+		return nil
+	}
+
+	if leadingLeftover {
+		if err := readLeftover(); err != nil {
+			return "", pos, err
+		}
+		if err := readFullGroups(); err != nil {
+			return "", pos, err
+		}
+	} else {
+		if err := readFullGroups(); err != nil {
+			return "", pos, err
+		}
+		if err := readLeftover(); err != nil {
+			return "", pos, err
+		}
+	}
+
+	return sb.String(), pos, nil
+}
+
+func decodeBigEndianUint(b []byte) uint32 {
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+	return v
+}
+
+// Skip skips a DECIMAL/NUMERIC value without parsing it.
+func (p *DecimalParser) Skip(input []byte, offset int, col *schema.Column, varLen int) (int, error) {
+	if col.Type != schema.TypeDecimal && col.Type != schema.TypeNumeric {
+		return 0, schema.ErrUnsupportedType
+	}
+	return col.StorageSize(), nil
+}
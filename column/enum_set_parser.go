@@ -0,0 +1,83 @@
+// enum_set_parser.go - Parser for the ENUM and SET column types
+package column
+
+import (
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// EnumSetParser handles ENUM and SET columns.
+type EnumSetParser struct {
+	BaseParser
+}
+
+// Parse decodes an ENUM value to its string member, or a SET value to the
+// []string of its set members, in declaration order.
+func (p *EnumSetParser) Parse(input []byte, offset int, col *schema.Column, varLen int) (interface{}, int, error) {
+	switch col.Type {
+	case schema.TypeEnum:
+		size := enumStorageSize(col)
+		idx, err := p.readEnumIndex(input, offset, size)
+		if err != nil {
+			return nil, 0, err
+		}
+		if idx == 0 {
+			return "", size, nil // 0 means the empty-string '' ENUM error value
+		}
+		if int(idx) > len(col.EnumValues) {
+			return nil, 0, schema.ErrUnsupportedType
+		}
+		return col.EnumValues[idx-1], size, nil
+
+	case schema.TypeSet:
+		size := (len(col.SetValues) + 7) / 8
+		raw, err := p.readBytes(input, offset, size)
+		if err != nil {
+			return nil, 0, err
+		}
+		// SET is stored as a little-endian bitmap, one bit per member in
+		// declaration order.
+		var val uint64
+		for i := len(raw) - 1; i >= 0; i-- {
+			val = val<<8 | uint64(raw[i])
+		}
+		var members []string
+		for i, name := range col.SetValues {
+			if val&(1<<uint(i)) != 0 {
+				members = append(members, name)
+			}
+		}
+		return members, size, nil
+
+	default:
+		return nil, 0, schema.ErrUnsupportedType
+	}
+}
+
+// Skip skips an ENUM/SET value without parsing it.
+func (p *EnumSetParser) Skip(input []byte, offset int, col *schema.Column, varLen int) (int, error) {
+	switch col.Type {
+	case schema.TypeEnum:
+		return enumStorageSize(col), nil
+	case schema.TypeSet:
+		return (len(col.SetValues) + 7) / 8, nil
+	default:
+		return 0, schema.ErrUnsupportedType
+	}
+}
+
+// enumStorageSize returns ENUM's on-disk width: 1 byte for up to 255
+// members, 2 bytes otherwise.
+func enumStorageSize(col *schema.Column) int {
+	if len(col.EnumValues) > 255 {
+		return 2
+	}
+	return 1
+}
+
+func (p *EnumSetParser) readEnumIndex(input []byte, offset, size int) (uint16, error) {
+	if size == 1 {
+		val, err := p.readUint8(input, offset)
+		return uint16(val), err
+	}
+	return p.readUint16(input, offset)
+}
@@ -9,6 +9,10 @@ var (
 	intParser      = &IntParser{}
 	stringParser   = &StringParser{}
 	dateTimeParser = &DateTimeParser{}
+	decimalParser  = &DecimalParser{}
+	bitParser      = &BitParser{}
+	enumSetParser  = &EnumSetParser{}
+	jsonParser     = &JSONParser{}
 	// Add more parsers as needed
 )
 
@@ -33,12 +37,21 @@ func GetParser(col *schema.Column) Parser {
 		schema.TypeTimestamp:
 		return dateTimeParser
 
+	// Packed decimal types
+	case schema.TypeDecimal, schema.TypeNumeric:
+		return decimalParser
+
+	case schema.TypeBit:
+		return bitParser
+
+	case schema.TypeEnum, schema.TypeSet:
+		return enumSetParser
+
+	case schema.TypeJSON:
+		return jsonParser
+
 	// TODO: Add more parsers for:
-	// - DECIMAL/NUMERIC
 	// - FLOAT/DOUBLE
-	// - ENUM/SET
-	// - BIT
-	// - JSON
 
 	default:
 		return nil
@@ -27,15 +27,11 @@ func (p *IntParser) Parse(input []byte, offset int, col *schema.Column, varLen i
 			return val, 2, err
 		}
 		if col.Type == schema.TypeYear {
-			// YEAR is stored as unsigned byte, 0 = year 0000, otherwise add 1900
 			val, err := p.readUint8(input, offset)
 			if err != nil {
 				return nil, 0, err
 			}
-			if val == 0 {
-				return uint16(0), 1, nil
-			}
-			return uint16(uint16(val) + 1900), 1, nil
+			return uint16(yearFromStoredByte(val)), 1, nil
 		}
 		val, err := p.readInt16(input, offset)
 		return val, 2, err
@@ -78,6 +74,20 @@ func (p *IntParser) Parse(input []byte, offset int, col *schema.Column, varLen i
 	}
 }
 
+// yearFromStoredByte converts a YEAR column's stored byte (0 = year 0000,
+// otherwise a two-digit offset) to its real four-digit year, using MySQL's
+// century convention: 00-69 -> 2000-2069, 70-99 -> 1970-1999, and so on for
+// every century beyond (70-255 -> 1970-2155).
+func yearFromStoredByte(val uint8) int {
+	if val == 0 {
+		return 0
+	}
+	if val <= 69 {
+		return 2000 + int(val)
+	}
+	return 1900 + int(val)
+}
+
 // Skip skips integer value without parsing
 func (p *IntParser) Skip(input []byte, offset int, col *schema.Column, varLen int) (int, error) {
 	switch col.Type {
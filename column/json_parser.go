@@ -0,0 +1,245 @@
+// json_parser.go - Parser for the JSON column type (MySQL's binary JSON
+// format, not a re-parse of the textual form)
+package column
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// JSON binary format type tags (json_binary.h's enum_type).
+const (
+	jsonSmallObject = 0x00
+	jsonLargeObject = 0x01
+	jsonSmallArray  = 0x02
+	jsonLargeArray  = 0x03
+	jsonLiteral     = 0x04
+	jsonInt16       = 0x05
+	jsonUint16      = 0x06
+	jsonInt32       = 0x07
+	jsonUint32      = 0x08
+	jsonInt64       = 0x09
+	jsonUint64      = 0x0A
+	jsonDouble      = 0x0B
+	jsonString      = 0x0C
+	jsonOpaque      = 0x0F
+)
+
+const (
+	jsonLiteralNull  = 0x00
+	jsonLiteralTrue  = 0x01
+	jsonLiteralFalse = 0x02
+)
+
+// JSONParser handles the JSON column type, decoding MySQL's binary JSON
+// representation into plain Go values (map[string]interface{},
+// []interface{}, string, float64, bool, nil).
+type JSONParser struct {
+	BaseParser
+}
+
+// Parse decodes a JSON column's bytes. JSON is always stored off the fixed
+// record layout as a variable-length column, so varLen is the document's
+// exact size.
+func (p *JSONParser) Parse(input []byte, offset int, col *schema.Column, varLen int) (interface{}, int, error) {
+	if col.Type != schema.TypeJSON {
+		return nil, 0, schema.ErrUnsupportedType
+	}
+	doc, err := p.readBytes(input, offset, varLen)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(doc) == 0 {
+		return nil, varLen, nil
+	}
+
+	value, err := decodeJSONValue(doc[0], doc[1:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode json: %w", err)
+	}
+	return value, varLen, nil
+}
+
+// Skip skips a JSON value without parsing it.
+func (p *JSONParser) Skip(input []byte, offset int, col *schema.Column, varLen int) (int, error) {
+	if col.Type != schema.TypeJSON {
+		return 0, schema.ErrUnsupportedType
+	}
+	return varLen, nil
+}
+
+// decodeJSONValue decodes one JSON value of the given type tag, whose
+// serialized body starts at data[0].
+func decodeJSONValue(typ byte, data []byte) (interface{}, error) {
+	switch typ {
+	case jsonSmallObject:
+		return decodeJSONContainer(data, false, true)
+	case jsonLargeObject:
+		return decodeJSONContainer(data, true, true)
+	case jsonSmallArray:
+		return decodeJSONContainer(data, false, false)
+	case jsonLargeArray:
+		return decodeJSONContainer(data, true, false)
+	case jsonLiteral:
+		switch data[0] {
+		case jsonLiteralNull:
+			return nil, nil
+		case jsonLiteralTrue:
+			return true, nil
+		case jsonLiteralFalse:
+			return false, nil
+		default:
+			return nil, fmt.Errorf("unknown json literal %#x", data[0])
+		}
+	case jsonInt16:
+		return int64(int16(binary.LittleEndian.Uint16(data))), nil
+	case jsonUint16:
+		return uint64(binary.LittleEndian.Uint16(data)), nil
+	case jsonInt32:
+		return int64(int32(binary.LittleEndian.Uint32(data))), nil
+	case jsonUint32:
+		return uint64(binary.LittleEndian.Uint32(data)), nil
+	case jsonInt64:
+		return int64(binary.LittleEndian.Uint64(data)), nil
+	case jsonUint64:
+		return binary.LittleEndian.Uint64(data), nil
+	case jsonDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+	case jsonString:
+		n, body, err := readJSONVarLen(data)
+		if err != nil {
+			return nil, err
+		}
+		return string(body[:n]), nil
+	case jsonOpaque:
+		// 1 byte MySQL column type the value was cast from, then a varlen
+		// byte count, then the raw bytes - surfaced as-is since there's no
+		// single idiomatic Go type for "opaque MySQL-typed blob".
+		n, body, err := readJSONVarLen(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, body[:n]...), nil
+	default:
+		return nil, fmt.Errorf("unknown json type tag %#x", typ)
+	}
+}
+
+// decodeJSONContainer decodes an object or array body. large selects
+// 4-byte vs. 2-byte element-count/size/offset fields; isObject selects
+// whether key entries precede the value entries.
+func decodeJSONContainer(data []byte, large, isObject bool) (interface{}, error) {
+	offSize := 2
+	if large {
+		offSize = 4
+	}
+	readUint := func(b []byte) uint32 {
+		if large {
+			return binary.LittleEndian.Uint32(b)
+		}
+		return uint32(binary.LittleEndian.Uint16(b))
+	}
+
+	count := int(readUint(data[0:offSize]))
+	pos := 2 * offSize // past element_count and size fields
+
+	type keyEntry struct{ offset, length int }
+	var keys []keyEntry
+	if isObject {
+		keys = make([]keyEntry, count)
+		for i := 0; i < count; i++ {
+			keys[i] = keyEntry{
+				offset: int(readUint(data[pos : pos+offSize])),
+				length: int(binary.LittleEndian.Uint16(data[pos+offSize : pos+offSize+2])),
+			}
+			pos += offSize + 2
+		}
+	}
+
+	type valueEntry struct {
+		typ     byte
+		inlined []byte
+		offset  int
+	}
+	values := make([]valueEntry, count)
+	for i := 0; i < count; i++ {
+		typ := data[pos]
+		raw := data[pos+1 : pos+1+offSize]
+		ve := valueEntry{typ: typ}
+		if jsonValueIsInlined(typ, large) {
+			ve.inlined = raw
+		} else {
+			ve.offset = int(readUint(raw))
+		}
+		values[i] = ve
+		pos += 1 + offSize
+	}
+
+	if isObject {
+		obj := make(map[string]interface{}, count)
+		for i, ve := range values {
+			key := string(data[keys[i].offset : keys[i].offset+keys[i].length])
+			val, err := decodeJSONContainerValue(data, ve.typ, ve.inlined, ve.offset)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = val
+		}
+		return obj, nil
+	}
+
+	arr := make([]interface{}, count)
+	for i, ve := range values {
+		val, err := decodeJSONContainerValue(data, ve.typ, ve.inlined, ve.offset)
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = val
+	}
+	return arr, nil
+}
+
+func decodeJSONContainerValue(data []byte, typ byte, inlined []byte, offset int) (interface{}, error) {
+	if inlined != nil {
+		return decodeJSONValue(typ, inlined)
+	}
+	if offset >= len(data) {
+		return nil, fmt.Errorf("json value offset %d out of range", offset)
+	}
+	return decodeJSONValue(typ, data[offset:])
+}
+
+// jsonValueIsInlined reports whether a value entry's fixed-width slot holds
+// the value itself rather than an offset to it elsewhere in the container.
+func jsonValueIsInlined(typ byte, large bool) bool {
+	switch typ {
+	case jsonLiteral, jsonInt16, jsonUint16:
+		return true
+	case jsonInt32, jsonUint32:
+		return large
+	default:
+		return false
+	}
+}
+
+// readJSONVarLen reads MySQL's variable-length integer (7 bits per byte,
+// little-endian, continuation bit in the MSB) and returns its value plus
+// the remaining bytes (the value's data).
+func readJSONVarLen(data []byte) (int, []byte, error) {
+	var length, shift uint
+	for i := 0; i < 5; i++ {
+		if i >= len(data) {
+			return 0, nil, fmt.Errorf("short read for json varlen")
+		}
+		b := data[i]
+		length |= uint(b&0x7F) << shift
+		if b&0x80 == 0 {
+			return int(length), data[i+1:], nil
+		}
+		shift += 7
+	}
+	return 0, nil, fmt.Errorf("json varlen too long")
+}
@@ -0,0 +1,53 @@
+// overflow.go - Off-page (BLOB/TEXT) overflow pointer parsing
+package column
+
+import "fmt"
+
+// overflowPointerSize is the width of a BTR_EXTERN_FIELD_REF, the fixed
+// structure InnoDB leaves in a record in place of a column's data once the
+// value no longer fits on the page.
+const overflowPointerSize = 20
+
+// OverflowPointer is InnoDB's BTR_EXTERN_FIELD_REF: which page the value's
+// first overflow chunk lives on, and how many bytes the full value occupies.
+type OverflowPointer struct {
+	SpaceID uint32
+	PageNo  uint32
+	PageOff uint32
+	Length  uint64
+}
+
+// ParseOverflowPointer reads the 20-byte external-storage pointer InnoDB
+// stores in place of a column's data once it has been pushed off-page.
+func ParseOverflowPointer(b []byte) (OverflowPointer, error) {
+	if len(b) < overflowPointerSize {
+		return OverflowPointer{}, fmt.Errorf("short overflow pointer: %d bytes", len(b))
+	}
+	be32 := func(off int) uint32 {
+		return uint32(b[off])<<24 | uint32(b[off+1])<<16 | uint32(b[off+2])<<8 | uint32(b[off+3])
+	}
+	var length uint64
+	for i := 0; i < 8; i++ {
+		length = length<<8 | uint64(b[12+i])
+	}
+	return OverflowPointer{
+		SpaceID: be32(0),
+		PageNo:  be32(4),
+		PageOff: be32(8),
+		Length:  length,
+	}, nil
+}
+
+// OverflowResolver fetches the full value of an externally-stored column
+// given its BTR_EXTERN_FIELD_REF, following the overflow page chain.
+// Implemented outside this package, where a PageReader is available.
+type OverflowResolver interface {
+	ResolveOverflow(ptr OverflowPointer) ([]byte, error)
+}
+
+// PageSource reads a single raw page from a tablespace by space and page
+// number. It's the minimal dependency LobOverflowResolver needs, so callers
+// can back it with a PageReader, a BufferPool, or a test fixture.
+type PageSource interface {
+	ReadPage(spaceID, pageNo uint32) ([]byte, error)
+}
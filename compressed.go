@@ -1,5 +1,11 @@
 // compressed.go - Support for InnoDB compressed pages
 // Uses cgo to call the C++ shim library for decompression
+//
+// Requires both cgo and the innodb_cgo build tag, so a plain `go build`
+// never needs libzipshim/libstdc++/libz/liblz4 on the link line; see
+// compressed_nocgo.go for the default pure-Go backend.
+//go:build cgo && innodb_cgo
+// +build cgo,innodb_cgo
 
 package goinnodb
 
@@ -118,8 +124,17 @@ func GetCompressedSize(page []byte, physicalSize int) int {
 // TryDecompressPage attempts to decompress a page if it appears compressed
 // Returns the decompressed page or the original if not compressed
 func TryDecompressPage(data []byte) ([]byte, bool, error) {
-	// If already 16KB, probably not compressed
+	// If already 16KB, it's either uncompressed or MariaDB
+	// PAGE_COMPRESSED (which keeps the page at its logical size and
+	// compresses only the content following the FIL header). Try that
+	// path first; it's a plain FIL header check, cheap to rule out.
 	if len(data) == LogicalPageSize {
+		if fil, err := ParseFilHeader(data); err == nil && IsMariaDBPageCompressed(fil) {
+			decompressed, _, err := DecompressMariaDBPage(data)
+			if err == nil {
+				return decompressed, true, nil
+			}
+		}
 		return data, false, nil
 	}
 
@@ -128,11 +143,15 @@ func TryDecompressPage(data []byte) ([]byte, bool, error) {
 		return data, false, nil
 	}
 
-	// Try different physical sizes
+	// Try different physical sizes, probing codecs in order (zlib via the
+	// active backend, then zstd) since a ROW_FORMAT=COMPRESSED physical
+	// page carries no algorithm id of its own.
 	for _, size := range CompressedPageSizes {
 		if len(data) >= size {
-			decompressed, err := DecompressPage(data, size)
-			if err == nil {
+			if decompressed, err := activeDecompressor.Decompress(data, size); err == nil {
+				return decompressed, true, nil
+			}
+			if decompressed, err := DecompressPageZstd(data, size); err == nil {
 				return decompressed, true, nil
 			}
 		}
@@ -141,3 +160,12 @@ func TryDecompressPage(data []byte) ([]byte, bool, error) {
 	// Couldn't decompress, return original
 	return data, false, fmt.Errorf("unable to decompress page")
 }
+
+// cgoDecompressor is the PageDecompressor backed by the zipshim C++ library.
+type cgoDecompressor struct{}
+
+func (cgoDecompressor) Decompress(src []byte, physical int) ([]byte, error) {
+	return DecompressPage(src, physical)
+}
+
+func init() { activeDecompressor = cgoDecompressor{} }
@@ -1,6 +1,11 @@
 // decompress.go - Go wrapper for InnoDB page decompression
 // This provides a clean Go API for decompressing InnoDB pages
 // without any MySQL dependencies
+//
+// Like compressed.go, this needs both cgo and the innodb_cgo tag; see
+// decompress_v2_nocgo.go for the default pure-Go equivalents.
+//go:build cgo && innodb_cgo
+// +build cgo,innodb_cgo
 
 package goinnodb
 
@@ -33,22 +38,29 @@ import (
 
 // Error codes from the C library
 const (
-	DecompressSuccess          = 0
-	DecompressErrorInvalidSize = -1
+	DecompressSuccess            = 0
+	DecompressErrorInvalidSize   = -1
 	DecompressErrorNotCompressed = -2
-	DecompressErrorFailed      = -3
-	DecompressErrorBufferSmall = -4
-	DecompressErrorInvalidPage = -5
+	DecompressErrorFailed        = -3
+	DecompressErrorBufferSmall   = -4
+	DecompressErrorInvalidPage   = -5
 )
 
 // PageInfo contains metadata about an InnoDB page
 type PageInfo struct {
-	PageNumber    uint32 // Page number from header
-	PageType      uint16 // Page type (FIL_PAGE_INDEX, etc.)
-	SpaceID       uint32 // Tablespace ID
-	IsCompressed  bool   // Whether the page is compressed
-	PhysicalSize  int    // Size on disk
-	LogicalSize   int    // Size when uncompressed (always 16KB)
+	PageNumber   uint32 // Page number from header
+	PageType     uint16 // Page type (FIL_PAGE_INDEX, etc.)
+	SpaceID      uint32 // Tablespace ID
+	IsCompressed bool   // Whether the page is compressed
+	PhysicalSize int    // Size on disk
+	LogicalSize  int    // Size when uncompressed (always 16KB)
+
+	// ChecksumValid and ChecksumAlgorithm report the result of checking the
+	// page's own stored checksum against VerifyChecksum. They're only
+	// meaningful for a full logical-size (uncompressed) page; compressed
+	// physical pages use a different checksum scheme this doesn't check.
+	ChecksumValid     bool
+	ChecksumAlgorithm ChecksumAlgorithm
 }
 
 // DecompressError represents an error from the decompression library
@@ -113,14 +125,18 @@ func GetPageInfo(data []byte) (*PageInfo, error) {
 		return nil, newDecompressError(code)
 	}
 
-	return &PageInfo{
+	info := &PageInfo{
 		PageNumber:   uint32(cInfo.page_number),
 		PageType:     uint16(cInfo.page_type),
 		SpaceID:      uint32(cInfo.space_id),
 		IsCompressed: cInfo.is_compressed != 0,
 		PhysicalSize: int(cInfo.physical_size),
 		LogicalSize:  int(cInfo.logical_size),
-	}, nil
+	}
+	if len(data) == PageSize {
+		info.ChecksumAlgorithm, info.ChecksumValid = VerifyChecksum(data)
+	}
+	return info, nil
 }
 
 // DecompressPageV2 decompresses a compressed InnoDB page
@@ -199,11 +215,11 @@ func DetectCompressedSize(size int64) (int, bool) {
 			return pageSize, true
 		}
 	}
-	
+
 	// Check for uncompressed
 	if size%16384 == 0 {
 		return 16384, false
 	}
-	
+
 	return 0, false
-}
\ No newline at end of file
+}
@@ -0,0 +1,96 @@
+// decompress_v2_nocgo.go - Pure-Go counterpart to decompress_v2.go's
+// PageInfo-oriented API, built whenever the cgo backend isn't (the
+// default).
+//go:build !(cgo && innodb_cgo)
+// +build !cgo !innodb_cgo
+
+package goinnodb
+
+import "fmt"
+
+// PageInfo contains metadata about an InnoDB page
+type PageInfo struct {
+	PageNumber   uint32 // Page number from header
+	PageType     uint16 // Page type (FIL_PAGE_INDEX, etc.)
+	SpaceID      uint32 // Tablespace ID
+	IsCompressed bool   // Whether the page is compressed
+	PhysicalSize int    // Size on disk
+	LogicalSize  int    // Size when uncompressed (always 16KB)
+
+	// ChecksumValid and ChecksumAlgorithm report the result of checking the
+	// page's own stored checksum against VerifyChecksum. They're only
+	// meaningful for a full logical-size (uncompressed) page; compressed
+	// physical pages use a different checksum scheme this doesn't check.
+	ChecksumValid     bool
+	ChecksumAlgorithm ChecksumAlgorithm
+}
+
+// IsPageCompressedV2 checks if a page appears to be compressed.
+// (V2 suffix kept for parity with the cgo build's API.)
+func IsPageCompressedV2(data []byte) (bool, error) {
+	if len(data) == 0 {
+		return false, fmt.Errorf("empty page data")
+	}
+	return IsPageCompressed(data), nil
+}
+
+// GetPageInfo retrieves metadata about an InnoDB page straight from its FIL
+// header, without needing the C page-info shim.
+func GetPageInfo(data []byte) (*PageInfo, error) {
+	if len(data) < 38 { // Minimum size for FIL header
+		return nil, fmt.Errorf("page too small: %d bytes", len(data))
+	}
+
+	fil, err := ParseFilHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse FIL header: %w", err)
+	}
+
+	info := &PageInfo{
+		PageNumber:   fil.PageNumber,
+		PageType:     uint16(fil.PageType),
+		SpaceID:      fil.SpaceID,
+		IsCompressed: IsPageCompressed(data),
+		PhysicalSize: len(data),
+		LogicalSize:  LogicalPageSize,
+	}
+	if len(data) == PageSize {
+		info.ChecksumAlgorithm, info.ChecksumValid = VerifyChecksum(data)
+	}
+	return info, nil
+}
+
+// DecompressPageV2 decompresses a compressed InnoDB page, auto-detecting
+// its physical size the same way TryDecompressPage does.
+// (V2 suffix kept for parity with the cgo build's API.)
+func DecompressPageV2(compressedData []byte) ([]byte, error) {
+	decompressed, _, err := TryDecompressPage(compressedData)
+	return decompressed, err
+}
+
+// ProcessPage handles both compressed and uncompressed pages. It
+// automatically detects if decompression is needed.
+func ProcessPage(pageData []byte) ([]byte, error) {
+	decompressed, _, err := TryDecompressPage(pageData)
+	return decompressed, err
+}
+
+// GetDecompressVersion reports which decompression backend is linked in.
+// The pure-Go backend has no separate library version to report.
+func GetDecompressVersion() string {
+	return "pure-go (compress/zlib)"
+}
+
+// DetectCompressedSize detects the likely physical page size from a file's
+// byte size.
+func DetectCompressedSize(size int64) (int, bool) {
+	for _, pageSize := range CompressedPageSizes {
+		if size%int64(pageSize) == 0 {
+			return pageSize, true
+		}
+	}
+	if size%int64(LogicalPageSize) == 0 {
+		return LogicalPageSize, false
+	}
+	return 0, false
+}
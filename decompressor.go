@@ -0,0 +1,17 @@
+// decompressor.go - Backend-agnostic page decompression
+package goinnodb
+
+// PageDecompressor decompresses a physically-sized ROW_FORMAT=COMPRESSED
+// page (1/2/4/8 KiB) back into a full 16 KiB logical page. Exactly one
+// implementation is linked in per build tag: compressed.go's cgo backend
+// (//go:build cgo && innodb_cgo) or compressed_nocgo.go's pure-Go backend
+// (the default). IsPageCompressed, DecompressPage, TryDecompressPage, and
+// the V2 helpers all route through activeDecompressor so callers never
+// need to care which one is linked in.
+type PageDecompressor interface {
+	Decompress(src []byte, physical int) ([]byte, error)
+}
+
+// activeDecompressor is set by an init() in whichever of compressed.go or
+// compressed_nocgo.go the build tags selected.
+var activeDecompressor PageDecompressor
@@ -23,3 +23,11 @@ func be64(b []byte, off int) (uint64, error) {
 	}
 	return binary.BigEndian.Uint64(b[off : off+8]), nil
 }
+
+func putBe32(b []byte, off int, v uint32) {
+	binary.BigEndian.PutUint32(b[off:off+4], v)
+}
+
+func putBe16(b []byte, off int, v uint16) {
+	binary.BigEndian.PutUint16(b[off:off+2], v)
+}
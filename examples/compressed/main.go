@@ -7,6 +7,8 @@ import (
 	"os"
 
 	innodb "github.com/wilhasse/go-innodb"
+	"github.com/wilhasse/go-innodb/format"
+	ipage "github.com/wilhasse/go-innodb/page"
 )
 
 func main() {
@@ -62,28 +64,28 @@ func main() {
 	// Show page type name
 	pageTypeName := "UNKNOWN"
 	switch page.PageType() {
-	case innodb.PageTypeIndex:
+	case format.PageTypeIndex:
 		pageTypeName = "INDEX"
-	case innodb.PageTypeAllocated:
+	case format.PageTypeAllocated:
 		pageTypeName = "ALLOCATED"
-	case innodb.PageTypeUndoLog:
+	case format.PageTypeUndoLog:
 		pageTypeName = "UNDO_LOG"
-	case innodb.PageTypeSDI:
+	case format.PageTypeSDI:
 		pageTypeName = "SDI"
 	}
 	fmt.Printf("Page Type Name: %s\n", pageTypeName)
 
 	// If it's an index page, show more details
-	if page.PageType() == innodb.PageTypeIndex {
-		indexPage, err := innodb.ParseIndexPage(page)
+	if page.PageType() == format.PageTypeIndex {
+		indexPage, err := ipage.ParseIndexPage(page)
 		if err != nil {
 			fmt.Printf("Error parsing index page: %v\n", err)
 		} else {
 			fmt.Printf("\nIndex Page Details:\n")
 			formatName := "UNKNOWN"
-			if indexPage.Hdr.Format == innodb.FormatCompact {
+			if indexPage.Hdr.Format == format.FormatCompact {
 				formatName = "COMPACT"
-			} else if indexPage.Hdr.Format == innodb.FormatRedundant {
+			} else if indexPage.Hdr.Format == format.FormatRedundant {
 				formatName = "REDUNDANT"
 			}
 			fmt.Printf("  Format: %s\n", formatName)
@@ -125,7 +125,7 @@ func main() {
 		fmt.Printf("\nParsed InnerPage successfully:\n")
 		fmt.Printf("  Checksum: 0x%08X\n", innerPage.FIL.Checksum)
 		fmt.Printf("  LSN: %d\n", innerPage.FIL.LastModLSN)
-		fmt.Printf("  Page Type: %s\n", innerPage.FIL.PageType)
+		fmt.Printf("  Page Type: %d\n", innerPage.FIL.PageType)
 		
 		// If it's an index page, show more details
 		if fmt.Sprintf("%v", innerPage.FIL.PageType) == "INDEX" {
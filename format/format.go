@@ -0,0 +1,67 @@
+// format.go - On-disk page/record layout constants shared by the page,
+// record, and column packages. These mirror the root package's types.go;
+// the two packages describe the same on-disk layout from different
+// abstraction levels and are kept in sync by hand.
+package format
+
+import "errors"
+
+// ErrShortRead is returned by parsers when a buffer ends before a
+// fixed-size field they need to read.
+var ErrShortRead = errors.New("short read")
+
+// Sizes and constants
+const (
+	PageSize          = 16 * 1024 // 16384
+	FilHeaderSize     = 38
+	FilTrailerSize    = 8
+	RecordHeaderSize  = 5 // compact header (3B bits + 2B next)
+	SystemRecordBytes = 8 // "infimum\x00" or "supremum" literal
+	PageDirSlotSize   = 2
+
+	// Index (page) header = 36 bytes
+	// FSEG header (immediately after) = 20 bytes
+	PageHeaderSize = 56
+)
+
+// PageType identifies a page's FIL_PAGE_TYPE.
+type PageType uint16
+
+const (
+	PageTypeAllocated           PageType = 0
+	PageTypeIndex               PageType = 17855
+	PageTypeUndoLog             PageType = 2
+	PageTypeSDI                 PageType = 17853
+	PageTypeCompressed          PageType = 34354
+	PageTypeCompressedEncrypted PageType = 37401
+	PageTypeEncrypted           PageType = 37402
+)
+
+// PageFormat distinguishes COMPACT/Barracuda row format from REDUNDANT/Antelope.
+type PageFormat uint8
+
+const (
+	FormatRedundant PageFormat = 0
+	FormatCompact   PageFormat = 1
+)
+
+// PageDirection is the page-level insertion-direction hint (PAGE_DIRECTION).
+type PageDirection uint16
+
+const (
+	DirLeft        PageDirection = 1
+	DirRight       PageDirection = 2
+	DirSameRec     PageDirection = 3
+	DirSamePage    PageDirection = 4
+	DirNoDirection PageDirection = 5
+)
+
+// RecordType is a compact/redundant record header's 3-bit REC_STATUS.
+type RecordType uint8
+
+const (
+	RecConventional RecordType = 0
+	RecNodePointer  RecordType = 1
+	RecInfimum      RecordType = 2
+	RecSupremum     RecordType = 3
+)
@@ -4,10 +4,21 @@ package goinnodb
 type GenericRecord struct {
 	PageNumber      uint32
 	Header          RecordHeader
-	PrimaryKeyPos   int    // absolute offset where this record's content starts
-	ChildPageNumber uint32 // for internal pages (not decoded here)
+	PrimaryKeyPos   int                    // absolute offset where this record's content starts
+	ChildPageNumber uint32                 // for internal pages (not decoded here)
+	Data            []byte                 // raw record data (excluding header)
+	Values          map[string]interface{} // parsed column values (column name -> value), set once a schema is available
 }
 
 func (r GenericRecord) NextRecordPos() int {
 	return r.PrimaryKeyPos + r.Header.NextRecOffset
 }
+
+// GetValue returns the parsed value for a column, if Values has been populated.
+func (r GenericRecord) GetValue(columnName string) (interface{}, bool) {
+	if r.Values == nil {
+		return nil, false
+	}
+	val, exists := r.Values[columnName]
+	return val, exists
+}
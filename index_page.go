@@ -3,6 +3,7 @@ package goinnodb
 import (
 	"bytes"
 	"fmt"
+	"sort"
 )
 
 type IndexPage struct {
@@ -22,38 +23,37 @@ func ParseIndexPage(ip *InnerPage) (*IndexPage, error) {
 	if err != nil {
 		return nil, err
 	}
-	if hdr.Format != FormatCompact {
-		return nil, fmt.Errorf("only compact pages supported (format=%d)", hdr.Format)
-	}
 	fseg, err := ParseFsegHeader(ip.Data, FilHeaderSize+36)
 	if err != nil {
 		return nil, err
 	}
 
 	cur := FilHeaderSize + PageHeaderSize
+	recordHeaderSize := RecordHeaderSize
+	if hdr.Format == FormatRedundant {
+		recordHeaderSize = RedundantHeaderSize
+	}
 
 	// INFIMUM
-	infHdr, err := ParseRecordHeader(ip.Data, cur)
-	if err != nil {
-		return nil, err
-	}
-	cur += RecordHeaderSize
+	cur += recordHeaderSize
 	if !bytes.Equal(ip.Data[cur:cur+SystemRecordBytes], LitInfimum) {
 		return nil, fmt.Errorf("INFIMUM literal mismatch at %d", cur)
 	}
-	inf := GenericRecord{PageNumber: ip.PageNo, Header: infHdr, PrimaryKeyPos: cur}
-	cur += SystemRecordBytes
-
-	// SUPREMUM
-	supHdr, err := ParseRecordHeader(ip.Data, cur)
+	inf, err := parseSystemRecord(ip.PageNo, ip.Data, hdr.Format, cur)
 	if err != nil {
 		return nil, err
 	}
-	cur += RecordHeaderSize
+	cur += SystemRecordBytes
+
+	// SUPREMUM
+	cur += recordHeaderSize
 	if !bytes.Equal(ip.Data[cur:cur+SystemRecordBytes], LitSupremum) {
 		return nil, fmt.Errorf("SUPREMUM literal mismatch at %d", cur)
 	}
-	sup := GenericRecord{PageNumber: ip.PageNo, Header: supHdr, PrimaryKeyPos: cur}
+	sup, err := parseSystemRecord(ip.PageNo, ip.Data, hdr.Format, cur)
+	if err != nil {
+		return nil, err
+	}
 	cur += SystemRecordBytes
 	_ = cur
 
@@ -72,6 +72,36 @@ func ParseIndexPage(ip *InnerPage) (*IndexPage, error) {
 	}, nil
 }
 
+// parseSystemRecord builds the GenericRecord for INFIMUM/SUPREMUM at
+// contentPos, reading the header in whichever width the page's row format
+// uses so both COMPACT and REDUNDANT pages end up with the same record shape.
+func parseSystemRecord(pageNo uint32, data []byte, pf PageFormat, contentPos int) (GenericRecord, error) {
+	if pf == FormatRedundant {
+		rr, err := ParseRedundantRecord(pageNo, data, contentPos-RedundantHeaderSize)
+		if err != nil {
+			return GenericRecord{}, err
+		}
+		recType := RecConventional
+		if bytes.Equal(data[contentPos:contentPos+SystemRecordBytes], LitInfimum) {
+			recType = RecInfimum
+		} else if bytes.Equal(data[contentPos:contentPos+SystemRecordBytes], LitSupremum) {
+			recType = RecSupremum
+		}
+		return rr.ToGenericRecord(recType), nil
+	}
+
+	hdr, err := ParseRecordHeader(data, contentPos-RecordHeaderSize)
+	if err != nil {
+		return GenericRecord{}, err
+	}
+	return GenericRecord{
+		PageNumber:    pageNo,
+		Header:        hdr,
+		PrimaryKeyPos: contentPos,
+		Data:          data[contentPos : contentPos+SystemRecordBytes],
+	}, nil
+}
+
 func (p *IndexPage) IsLeaf() bool { return p.Hdr.PageLevel == 0 }
 func (p *IndexPage) IsRoot() bool { return p.Inner.FIL.Prev == nil && p.Inner.FIL.Next == nil }
 
@@ -79,3 +109,107 @@ func (p *IndexPage) IsRoot() bool { return p.Inner.FIL.Prev == nil && p.Inner.FI
 func (p *IndexPage) UsedBytes() int {
 	return int(p.Hdr.HeapTop) + FilTrailerSize + int(p.Hdr.NumDirSlots)*PageDirSlotSize - int(p.Hdr.GarbageSpace)
 }
+
+// RecordExtent describes where one record's header and payload live on a
+// page: HeaderPos is the record's 5-byte compact header, DataPos is its
+// content origin, and DataEnd is the first byte belonging to whatever
+// comes next.
+type RecordExtent struct {
+	HeaderPos int
+	DataPos   int
+	DataEnd   int
+}
+
+// RecordExtents builds a bounded extent for every record reachable from
+// INFIMUM, using the page directory slots, the heap top, and
+// infimum/supremum as a sorted set of candidate record boundaries. A
+// record's DataEnd is the smallest candidate strictly greater than its
+// DataPos, clamped to HeapTop.
+// Only meaningful for COMPACT pages; REDUNDANT records already carry their
+// own bounded Data via WalkRecords/ParseRedundantRecord's field-offset array.
+func (p *IndexPage) RecordExtents(max int) ([]RecordExtent, error) {
+	if p.Hdr.Format == FormatRedundant {
+		return nil, fmt.Errorf("RecordExtents is COMPACT-only; REDUNDANT records bound themselves via FieldOffsets")
+	}
+	type chainEntry struct {
+		headerPos int
+		dataPos   int
+	}
+
+	var chain []chainEntry
+	cur := p.Infimum
+	chain = append(chain, chainEntry{headerPos: p.Infimum.PrimaryKeyPos - RecordHeaderSize, dataPos: p.Infimum.PrimaryKeyPos})
+
+	for steps := 0; steps < max; steps++ {
+		if cur.Header.NextRecOffset == 0 {
+			break
+		}
+		nextContent := cur.NextRecordPos()
+		if nextContent < FilHeaderSize+PageHeaderSize || nextContent >= PageSize-FilTrailerSize {
+			return nil, fmt.Errorf("next content position out of bounds: %d", nextContent)
+		}
+		nextHeaderPos := nextContent - RecordHeaderSize
+		hdr, err := ParseRecordHeader(p.Inner.Data, nextHeaderPos)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, chainEntry{headerPos: nextHeaderPos, dataPos: nextContent})
+		if hdr.Type == RecSupremum {
+			break
+		}
+		cur = GenericRecord{PageNumber: cur.PageNumber, Header: hdr, PrimaryKeyPos: nextContent}
+	}
+
+	boundarySet := make(map[int]struct{}, len(chain)+len(p.DirSlots)+2)
+	for _, c := range chain {
+		boundarySet[c.dataPos] = struct{}{}
+	}
+	for _, slot := range p.DirSlots {
+		boundarySet[int(slot)] = struct{}{}
+	}
+	boundarySet[p.Infimum.PrimaryKeyPos] = struct{}{}
+	boundarySet[p.Supremum.PrimaryKeyPos] = struct{}{}
+
+	boundaries := make([]int, 0, len(boundarySet))
+	for off := range boundarySet {
+		boundaries = append(boundaries, off)
+	}
+	sort.Ints(boundaries)
+
+	extents := make([]RecordExtent, len(chain))
+	for i, c := range chain {
+		end := int(p.Hdr.HeapTop)
+		idx := sort.SearchInts(boundaries, c.dataPos+1)
+		if idx < len(boundaries) {
+			end = boundaries[idx]
+		}
+		if end > int(p.Hdr.HeapTop) {
+			end = int(p.Hdr.HeapTop)
+		}
+		extents[i] = RecordExtent{HeaderPos: c.headerPos, DataPos: c.dataPos, DataEnd: end}
+	}
+	return extents, nil
+}
+
+// WalkGarbage follows the page's free list of deleted-but-not-yet-reclaimed
+// records, starting at FirstGarbageOff.
+func (p *IndexPage) WalkGarbage(max int) ([]GenericRecord, error) {
+	var out []GenericRecord
+	pos := int(p.Hdr.FirstGarbageOff)
+	for steps := 0; pos != 0 && steps < max; steps++ {
+		headerPos := pos - RecordHeaderSize
+		if headerPos < 0 || pos >= PageSize-FilTrailerSize {
+			return out, fmt.Errorf("garbage record position out of bounds: %d", pos)
+		}
+		hdr, err := ParseRecordHeader(p.Inner.Data, headerPos)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, GenericRecord{PageNumber: p.Inner.PageNo, Header: hdr, PrimaryKeyPos: pos})
+		if hdr.NextRecOffset == 0 {
+			break
+		}
+		pos += hdr.NextRecOffset
+	}
+	return out, nil
+}
@@ -10,10 +10,28 @@ type InnerPage struct {
 	Data    []byte // full 16KiB page bytes
 }
 
-func NewInnerPage(pageNo uint32, page []byte) (*InnerPage, error) {
+// InnerPageOption configures NewInnerPage's validation behavior.
+type InnerPageOption func(*innerPageConfig)
+
+type innerPageConfig struct {
+	checksumMode ChecksumMode
+}
+
+// WithChecksumMode sets how strictly NewInnerPage enforces the page's stored
+// checksum. The default is ChecksumModeAny (no enforcement).
+func WithChecksumMode(mode ChecksumMode) InnerPageOption {
+	return func(c *innerPageConfig) { c.checksumMode = mode }
+}
+
+func NewInnerPage(pageNo uint32, page []byte, opts ...InnerPageOption) (*InnerPage, error) {
 	if len(page) != PageSize {
 		return nil, fmt.Errorf("expected %dB page, got %d", PageSize, len(page))
 	}
+	cfg := innerPageConfig{checksumMode: ChecksumModeAny}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	h, err := ParseFilHeader(page)
 	if err != nil {
 		return nil, err
@@ -25,6 +43,9 @@ func NewInnerPage(pageNo uint32, page []byte) (*InnerPage, error) {
 	if uint32(h.LastModLSN&0xffffffff) != t.Low32LSN {
 		return nil, fmt.Errorf("low32 LSN mismatch: hdr=%#x trl=%#x", uint32(h.LastModLSN), t.Low32LSN)
 	}
+	if err := VerifyPage(page, cfg.checksumMode); err != nil {
+		return nil, fmt.Errorf("page %d: %w", pageNo, err)
+	}
 	return &InnerPage{PageNo: pageNo, FIL: h, Trailer: t, Data: page}, nil
 }
 
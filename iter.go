@@ -1,69 +1,73 @@
 // iter.go - Record iteration and traversal utilities
 package goinnodb
 
-import "fmt"
-
-// WalkRecords walks records on a page following the compact record header's relative next offset.
+// WalkRecords walks records on a page, dispatching on the page's row format
+// so both COMPACT and REDUNDANT pages yield the same GenericRecord stream.
+// COMPACT records have their Data slice bounded using RecordExtents (page
+// directory slots + heap walk + infimum/supremum), not a fixed-size guess.
 // If skipSystem is true, INFIMUM and SUPREMUM are not returned.
 // max limits the number of records to traverse (safety).
 func (p *IndexPage) WalkRecords(max int, skipSystem bool) ([]GenericRecord, error) {
-	if p.Hdr.Format != FormatCompact {
-		return nil, fmt.Errorf("only compact format supported in WalkRecords")
-	}
 	var out []GenericRecord
-	cur := p.Infimum
-	if !skipSystem {
-		out = append(out, cur)
-	}
-	for steps := 0; steps < max; steps++ {
-		nextContent := cur.NextRecordPos()
-		if cur.Header.NextRecOffset == 0 {
-			break // usually SUPREMUM has next==0
-		}
-		if nextContent < FilHeaderSize+PageHeaderSize || nextContent >= PageSize-FilTrailerSize {
-			return out, fmt.Errorf("next content position out of bounds: %d", nextContent)
-		}
-		nextHeaderPos := nextContent - RecordHeaderSize
-		if nextHeaderPos < 0 {
-			return out, fmt.Errorf("negative next header pos")
+	if p.Hdr.Format == FormatRedundant {
+		recs, err := WalkRedundantRecords(p.Inner.PageNo, p.Inner.Data, p.Infimum.PrimaryKeyPos, p.Supremum.PrimaryKeyPos, max, skipSystem)
+		if err != nil {
+			return nil, err
 		}
-		hdr, err := ParseRecordHeader(p.Inner.Data, nextHeaderPos)
+		out = recs
+	} else {
+		extents, err := p.RecordExtents(max)
 		if err != nil {
-			return out, err
+			return nil, err
 		}
-		rec := GenericRecord{PageNumber: p.Inner.PageNo, Header: hdr, PrimaryKeyPos: nextContent}
 
-		// Read the actual record data
-		// For now, read up to the next record or a reasonable amount of bytes
-		dataSize := 0
-		if hdr.NextRecOffset > 0 && hdr.NextRecOffset > RecordHeaderSize {
-			// Size is roughly the distance to the next record minus the header
-			dataSize = hdr.NextRecOffset - RecordHeaderSize
-		} else if hdr.Type == RecSupremum {
-			// Supremum has fixed 8-byte data
-			dataSize = 8
-		} else {
-			// For the last user record or unknown cases, read a reasonable amount
-			// This is a heuristic - proper implementation needs column definitions
-			dataSize = 100 // Read up to 100 bytes of data
-			maxPos := len(p.Inner.Data) - nextContent
-			if dataSize > maxPos {
-				dataSize = maxPos
+		for i, ext := range extents {
+			var hdr RecordHeader
+			if i == 0 {
+				hdr = p.Infimum.Header
+			} else {
+				var perr error
+				hdr, perr = ParseRecordHeader(p.Inner.Data, ext.HeaderPos)
+				if perr != nil {
+					return out, perr
+				}
 			}
-		}
 
-		if dataSize > 0 && nextContent+dataSize <= len(p.Inner.Data) {
-			rec.Data = p.Inner.Data[nextContent : nextContent+dataSize]
-		}
+			rec := GenericRecord{PageNumber: p.Inner.PageNo, Header: hdr, PrimaryKeyPos: ext.DataPos}
+			if ext.DataEnd > ext.DataPos && ext.DataEnd <= len(p.Inner.Data) {
+				rec.Data = p.Inner.Data[ext.DataPos:ext.DataEnd]
+			}
 
-		if rec.Header.Type == RecSupremum {
-			if !skipSystem {
-				out = append(out, rec)
+			isSystem := hdr.Type == RecInfimum || hdr.Type == RecSupremum
+			if isSystem && skipSystem {
+				if hdr.Type == RecSupremum {
+					break
+				}
+				continue
+			}
+			out = append(out, rec)
+			if hdr.Type == RecSupremum {
+				break
 			}
-			break
 		}
-		out = append(out, rec)
-		cur = rec
+	}
+
+	if !p.IsLeaf() {
+		for i := range out {
+			decodeChildPageNumber(&out[i])
+		}
 	}
 	return out, nil
 }
+
+// decodeChildPageNumber fills in rec.ChildPageNumber for a non-leaf record.
+// InnoDB stores a node pointer's child page number as the last 4 bytes of
+// the record, right after its key fields, in place of the transaction id
+// and roll pointer a leaf record would carry there.
+func decodeChildPageNumber(rec *GenericRecord) {
+	if rec.Header.Type == RecInfimum || rec.Header.Type == RecSupremum || len(rec.Data) < 4 {
+		return
+	}
+	tail := rec.Data[len(rec.Data)-4:]
+	rec.ChildPageNumber = uint32(tail[0])<<24 | uint32(tail[1])<<16 | uint32(tail[2])<<8 | uint32(tail[3])
+}
@@ -30,38 +30,37 @@ func ParseIndexPage(ip *InnerPage) (*IndexPage, error) {
 	if err != nil {
 		return nil, err
 	}
-	if hdr.Format != format.FormatCompact {
-		return nil, fmt.Errorf("only compact pages supported (format=%d)", hdr.Format)
-	}
 	fseg, err := ParseFsegHeader(ip.Data, format.FilHeaderSize+36)
 	if err != nil {
 		return nil, err
 	}
 
 	cur := format.FilHeaderSize + format.PageHeaderSize
+	recordHeaderSize := format.RecordHeaderSize
+	if hdr.Format == format.FormatRedundant {
+		recordHeaderSize = record.RedundantHeaderSize
+	}
 
 	// INFIMUM
-	infHdr, err := record.ParseRecordHeader(ip.Data, cur)
-	if err != nil {
-		return nil, err
-	}
-	cur += format.RecordHeaderSize
+	cur += recordHeaderSize
 	if !bytes.Equal(ip.Data[cur:cur+format.SystemRecordBytes], LitInfimum) {
 		return nil, fmt.Errorf("INFIMUM literal mismatch at %d", cur)
 	}
-	inf := record.GenericRecord{PageNumber: ip.PageNo, Header: infHdr, PrimaryKeyPos: cur, Data: ip.Data[cur : cur+format.SystemRecordBytes]}
-	cur += format.SystemRecordBytes
-
-	// SUPREMUM
-	supHdr, err := record.ParseRecordHeader(ip.Data, cur)
+	inf, err := parseSystemRecord(ip.PageNo, ip.Data, hdr.Format, cur)
 	if err != nil {
 		return nil, err
 	}
-	cur += format.RecordHeaderSize
+	cur += format.SystemRecordBytes
+
+	// SUPREMUM
+	cur += recordHeaderSize
 	if !bytes.Equal(ip.Data[cur:cur+format.SystemRecordBytes], LitSupremum) {
 		return nil, fmt.Errorf("SUPREMUM literal mismatch at %d", cur)
 	}
-	sup := record.GenericRecord{PageNumber: ip.PageNo, Header: supHdr, PrimaryKeyPos: cur, Data: ip.Data[cur : cur+format.SystemRecordBytes]}
+	sup, err := parseSystemRecord(ip.PageNo, ip.Data, hdr.Format, cur)
+	if err != nil {
+		return nil, err
+	}
 	cur += format.SystemRecordBytes
 	_ = cur
 
@@ -80,6 +79,36 @@ func ParseIndexPage(ip *InnerPage) (*IndexPage, error) {
 	}, nil
 }
 
+// parseSystemRecord builds the GenericRecord for INFIMUM/SUPREMUM at
+// contentPos, reading the header in whichever width the page's row format
+// uses so both COMPACT and REDUNDANT pages end up with the same record shape.
+func parseSystemRecord(pageNo uint32, data []byte, pf format.PageFormat, contentPos int) (record.GenericRecord, error) {
+	if pf == format.FormatRedundant {
+		rr, err := record.ParseRedundantRecord(pageNo, data, contentPos-record.RedundantHeaderSize)
+		if err != nil {
+			return record.GenericRecord{}, err
+		}
+		recType := format.RecConventional
+		if bytes.Equal(data[contentPos:contentPos+format.SystemRecordBytes], LitInfimum) {
+			recType = format.RecInfimum
+		} else if bytes.Equal(data[contentPos:contentPos+format.SystemRecordBytes], LitSupremum) {
+			recType = format.RecSupremum
+		}
+		return rr.ToGenericRecord(recType), nil
+	}
+
+	hdr, err := record.ParseRecordHeader(data, contentPos-format.RecordHeaderSize)
+	if err != nil {
+		return record.GenericRecord{}, err
+	}
+	return record.GenericRecord{
+		PageNumber:    pageNo,
+		Header:        hdr,
+		PrimaryKeyPos: contentPos,
+		Data:          data[contentPos : contentPos+format.SystemRecordBytes],
+	}, nil
+}
+
 func (p *IndexPage) IsLeaf() bool { return p.Hdr.PageLevel == 0 }
 func (p *IndexPage) IsRoot() bool { return p.Inner.FIL.Prev == nil && p.Inner.FIL.Next == nil }
 
@@ -88,12 +117,31 @@ func (p *IndexPage) UsedBytes() int {
 	return int(p.Hdr.HeapTop) + format.FilTrailerSize + int(p.Hdr.NumDirSlots)*format.PageDirSlotSize - int(p.Hdr.GarbageSpace)
 }
 
-// WalkRecords walks records on a page following the compact record header's relative next offset.
+// WalkRecords walks records on a page, dispatching on the page's row format
+// so both COMPACT and REDUNDANT pages yield the same GenericRecord stream.
 // If skipSystem is true, INFIMUM and SUPREMUM are not returned.
 // max limits the number of records to traverse (safety).
 func (p *IndexPage) WalkRecords(max int, skipSystem bool) ([]record.GenericRecord, error) {
-	if p.Hdr.Format != format.FormatCompact {
-		return nil, fmt.Errorf("only compact format supported in WalkRecords")
+	if p.Hdr.Format == format.FormatRedundant {
+		return record.WalkRedundantRecordsFromData(p.Inner.PageNo, p.Inner.Data, p.Infimum.PrimaryKeyPos, p.Supremum.PrimaryKeyPos, max, skipSystem)
+	}
+	return record.WalkRecordsFromData(p.Inner.PageNo, p.Inner.Data, p.Infimum, p.Supremum, p.DirSlots, p.Hdr.HeapTop, max, skipSystem)
+}
+
+// RecordExtents returns the bounded {header, data start, data end} span of
+// every record reachable from INFIMUM, using the page directory and heap
+// top to bound each record instead of guessing a fixed payload size.
+// Only meaningful for COMPACT pages; REDUNDANT records already carry their
+// own bounded Data via WalkRecords/ParseRedundantRecord's field-offset array.
+func (p *IndexPage) RecordExtents(max int) ([]record.RecordExtent, error) {
+	if p.Hdr.Format == format.FormatRedundant {
+		return nil, fmt.Errorf("RecordExtents is COMPACT-only; REDUNDANT records bound themselves via FieldOffsets")
 	}
-	return record.WalkRecordsFromData(p.Inner.PageNo, p.Inner.Data, p.Infimum, max, skipSystem)
+	return record.RecordExtentsFromData(p.Inner.Data, p.Infimum, p.Supremum, p.DirSlots, p.Hdr.HeapTop, max)
+}
+
+// WalkGarbage follows the page's free list of deleted-but-not-yet-reclaimed
+// records, starting at FirstGarbageOff.
+func (p *IndexPage) WalkGarbage(max int) ([]record.GenericRecord, error) {
+	return record.WalkGarbageFromData(p.Inner.PageNo, p.Inner.Data, p.Hdr.FirstGarbageOff, max)
 }
@@ -0,0 +1,39 @@
+// inner.go - Whole-page framing: FIL header + body + FIL trailer
+package page
+
+import (
+	"fmt"
+
+	"github.com/wilhasse/go-innodb/format"
+)
+
+// InnerPage = FIL header + body + FIL trailer (exactly 16 KiB). Checksum
+// verification is the caller's responsibility (e.g. pagesource readers
+// check the raw physical block before any inflate/decrypt runs) - by the
+// time NewInnerPage sees the bytes they're assumed to already be validated.
+type InnerPage struct {
+	PageNo  uint32
+	FIL     FilHeader
+	Trailer FilTrailer
+	Data    []byte // full 16KiB page bytes
+}
+
+func NewInnerPage(pageNo uint32, data []byte) (*InnerPage, error) {
+	if len(data) != format.PageSize {
+		return nil, fmt.Errorf("expected %dB page, got %d", format.PageSize, len(data))
+	}
+	h, err := ParseFilHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ParseFilTrailer(data)
+	if err != nil {
+		return nil, err
+	}
+	if uint32(h.LastModLSN&0xffffffff) != t.Low32LSN {
+		return nil, fmt.Errorf("low32 LSN mismatch: hdr=%#x trl=%#x", uint32(h.LastModLSN), t.Low32LSN)
+	}
+	return &InnerPage{PageNo: pageNo, FIL: h, Trailer: t, Data: data}, nil
+}
+
+func (ip *InnerPage) PageType() format.PageType { return ip.FIL.PageType }
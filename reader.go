@@ -3,22 +3,66 @@ package goinnodb
 
 import (
 	"fmt"
+	"github.com/wilhasse/go-innodb/crypto"
 	"github.com/wilhasse/go-innodb/format"
-	"github.com/wilhasse/go-innodb/page"
 	"io"
 )
 
 type PageReader struct {
-	r io.ReaderAt
+	r                io.ReaderAt
+	physicalPageSize int // 0 = tablespace is not compressed, read PageSize directly
+	keyring          crypto.Keyring
 }
 
-func NewPageReader(r io.ReaderAt) *PageReader { return &PageReader{r: r} }
+// ReaderOption configures a PageReader at construction time.
+type ReaderOption func(*PageReader)
 
-func (pr *PageReader) ReadPage(pageNo uint32) (*page.InnerPage, error) {
-	buf := make([]byte, format.PageSize)
-	off := int64(pageNo) * int64(format.PageSize)
+// WithAutoDecompress makes ReadPage read physicalSize bytes per page instead
+// of the full 16KB logical page, and transparently run them through
+// TryDecompressPage so callers always get back a logical-size page.
+// physicalSize must be one of CompressedPageSizes.
+func WithAutoDecompress(physicalSize int) ReaderOption {
+	return func(pr *PageReader) { pr.physicalPageSize = physicalSize }
+}
+
+// WithKeyring makes ReadPage transparently decrypt pages marked
+// PageTypeEncrypted or PageTypeCompressedEncrypted, resolving their key
+// through kr before any further processing (e.g. decompression).
+func WithKeyring(kr crypto.Keyring) ReaderOption {
+	return func(pr *PageReader) { pr.keyring = kr }
+}
+
+func NewPageReader(r io.ReaderAt, opts ...ReaderOption) *PageReader {
+	pr := &PageReader{r: r}
+	for _, opt := range opts {
+		opt(pr)
+	}
+	return pr
+}
+
+func (pr *PageReader) ReadPage(pageNo uint32) (*InnerPage, error) {
+	size := format.PageSize
+	if pr.physicalPageSize > 0 {
+		size = pr.physicalPageSize
+	}
+	buf := make([]byte, size)
+	off := int64(pageNo) * int64(size)
 	if _, err := pr.r.ReadAt(buf, off); err != nil {
 		return nil, fmt.Errorf("read page %d: %w", pageNo, err)
 	}
-	return page.NewInnerPage(pageNo, buf)
+	if pr.keyring != nil && size == format.PageSize && crypto.IsPageEncrypted(buf) {
+		decrypted, err := crypto.DecryptPage(buf, pr.keyring)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt page %d: %w", pageNo, err)
+		}
+		buf = decrypted
+	}
+	if pr.physicalPageSize > 0 {
+		decompressed, err := DecompressPage(buf, pr.physicalPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("decompress page %d: %w", pageNo, err)
+		}
+		buf = decompressed
+	}
+	return NewInnerPage(pageNo, buf)
 }
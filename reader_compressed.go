@@ -3,6 +3,7 @@ package goinnodb
 
 import (
 	"fmt"
+	"github.com/wilhasse/go-innodb/crypto"
 	"github.com/wilhasse/go-innodb/format"
 	"github.com/wilhasse/go-innodb/page"
 	"io"
@@ -10,17 +11,19 @@ import (
 
 // CompressedPageReader extends PageReader with compression support
 type CompressedPageReader struct {
-	r                  io.ReaderAt
+	r                   io.ReaderAt
 	enableDecompression bool
-	physicalPageSize   int // Physical page size for compressed tables (0 = auto-detect)
+	physicalPageSize    int // Physical page size for compressed tables (0 = auto-detect)
+	keyring             crypto.Keyring
+	checksumMode        ChecksumMode
 }
 
 // NewCompressedPageReader creates a reader with compression support
 func NewCompressedPageReader(r io.ReaderAt) *CompressedPageReader {
 	return &CompressedPageReader{
-		r:                  r,
+		r:                   r,
 		enableDecompression: true,
-		physicalPageSize:   0, // Auto-detect
+		physicalPageSize:    0, // Auto-detect
 	}
 }
 
@@ -48,14 +51,34 @@ func (pr *CompressedPageReader) ReadPage(pageNo uint32) (*page.InnerPage, error)
 	if pr.physicalPageSize > 0 && pr.physicalPageSize < format.PageSize {
 		readSize = pr.physicalPageSize
 	}
-	
+
 	// Read the page data
 	buf := make([]byte, readSize)
 	off := int64(pageNo) * int64(readSize)
 	if _, err := pr.r.ReadAt(buf, off); err != nil {
 		return nil, fmt.Errorf("read page %d: %w", pageNo, err)
 	}
-	
+
+	// Verify the checksum on the raw physical block, before any inflate or
+	// decrypt touches it. Only full logical-size blocks are checked today;
+	// compressed physical pages use a different checksum scheme.
+	if len(buf) == format.PageSize {
+		if err := VerifyPage(buf, pr.checksumMode); err != nil {
+			return nil, fmt.Errorf("page %d: %w", pageNo, err)
+		}
+	}
+
+	// Decrypt before decompression is attempted: an encrypted+compressed
+	// page's physical bytes are ciphertext, and the zlib stream only exists
+	// once that's been reversed.
+	if pr.keyring != nil && len(buf) == format.PageSize && crypto.IsPageEncrypted(buf) {
+		decrypted, err := crypto.DecryptPage(buf, pr.keyring)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt page %d: %w", pageNo, err)
+		}
+		buf = decrypted
+	}
+
 	// Try to decompress if enabled and page appears compressed
 	if pr.enableDecompression {
 		decompressed, wasCompressed, err := pr.tryDecompress(buf)
@@ -67,7 +90,7 @@ func (pr *CompressedPageReader) ReadPage(pageNo uint32) (*page.InnerPage, error)
 			buf = decompressed
 		}
 	}
-	
+
 	// Parse the page (now guaranteed to be logical size if decompressed)
 	return page.NewInnerPage(pageNo, buf)
 }
@@ -85,20 +108,20 @@ func (pr *CompressedPageReader) ReadCompressedPage(pageNo uint32, physicalSize i
 	if !validSize {
 		return nil, fmt.Errorf("invalid physical page size: %d", physicalSize)
 	}
-	
+
 	// Read compressed data
 	buf := make([]byte, physicalSize)
 	off := int64(pageNo) * int64(physicalSize)
 	if _, err := pr.r.ReadAt(buf, off); err != nil {
 		return nil, fmt.Errorf("read compressed page %d: %w", pageNo, err)
 	}
-	
+
 	// Decompress
 	decompressed, err := DecompressPage(buf, physicalSize)
 	if err != nil {
 		return nil, fmt.Errorf("decompress page %d: %w", pageNo, err)
 	}
-	
+
 	// Parse decompressed page
 	return page.NewInnerPage(pageNo, decompressed)
 }
@@ -115,11 +138,25 @@ func (pr *CompressedPageReader) tryDecompress(data []byte) ([]byte, bool, error)
 			return data, false, err
 		}
 	}
-	
+
 	// Otherwise, try auto-detection
 	return TryDecompressPage(data)
 }
 
+// SetChecksumMode makes ReadPage verify a page's stored checksum against
+// mode before decompression is attempted, matching the MDEV-16416 ordering
+// of validating the on-disk (possibly still-compressed) bytes first.
+func (pr *CompressedPageReader) SetChecksumMode(mode ChecksumMode) {
+	pr.checksumMode = mode
+}
+
+// SetKeyring makes ReadPage transparently decrypt pages marked
+// PageTypeEncrypted or PageTypeCompressedEncrypted, resolving their key
+// through kr before decompression is attempted.
+func (pr *CompressedPageReader) SetKeyring(kr crypto.Keyring) {
+	pr.keyring = kr
+}
+
 // DisableDecompression turns off automatic decompression
 func (pr *CompressedPageReader) DisableDecompression() {
 	pr.enableDecompression = false
@@ -128,4 +165,4 @@ func (pr *CompressedPageReader) DisableDecompression() {
 // EnableDecompression turns on automatic decompression
 func (pr *CompressedPageReader) EnableDecompression() {
 	pr.enableDecompression = true
-}
\ No newline at end of file
+}
@@ -0,0 +1,71 @@
+// reader_sparse_linux.go - SEEK_DATA/SEEK_HOLE sparse map scanning on Linux
+//go:build linux
+// +build linux
+
+package goinnodb
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// Linux-only lseek(2) whence values; not exposed by the standard syscall
+// package on every platform, so we spell them out here.
+const (
+	seekData = 3 // SEEK_DATA
+	seekHole = 4 // SEEK_HOLE
+)
+
+// scanSparseMap walks the file with SEEK_DATA/SEEK_HOLE to build a per-page
+// allocation map without reading the actual bytes.
+func scanSparseMap(f *os.File, size int64) ([]PageFragment, error) {
+	numPages := int(size / PageSize)
+	if size%PageSize != 0 {
+		numPages++
+	}
+	fragments := make([]PageFragment, numPages)
+	fd := int(f.Fd())
+
+	for i := 0; i < numPages; i++ {
+		off := int64(i) * PageSize
+		end := off + PageSize
+		if end > size {
+			end = size
+		}
+
+		dataOff, err := syscall.Seek(fd, off, seekData)
+		if err != nil {
+			// ENXIO means there is no more data after off: the rest of the
+			// file (including this slot) is a hole.
+			fragments[i] = PageFragment{PageNo: uint32(i), FileOffset: off, IsHole: true}
+			continue
+		}
+		if dataOff >= end {
+			// The next data region starts at or after this slot: fully a hole.
+			fragments[i] = PageFragment{PageNo: uint32(i), FileOffset: off, IsHole: true}
+			continue
+		}
+
+		holeOff, err := syscall.Seek(fd, dataOff, seekHole)
+		if err != nil {
+			holeOff = end
+		}
+		if holeOff > end {
+			holeOff = end
+		}
+
+		fragments[i] = PageFragment{
+			PageNo:        uint32(i),
+			FileOffset:    off,
+			AllocatedSize: holeOff - off,
+		}
+	}
+
+	// Seeking moves the file's read/write offset as a side effect; restore
+	// it so callers using ReadAt (which is unaffected) or other file ops
+	// start from a sane position.
+	_, _ = f.Seek(0, io.SeekStart)
+
+	return fragments, nil
+}
@@ -0,0 +1,53 @@
+// reader_sparse_other.go - st_blocks-based fallback sparse map scanning
+//go:build !linux
+// +build !linux
+
+package goinnodb
+
+import (
+	"os"
+	"syscall"
+)
+
+// scanSparseMap falls back to syscall.Stat_t.Blocks when SEEK_DATA/SEEK_HOLE
+// is unavailable. Without per-offset hole information we can't tell exactly
+// which page slots are holes, so we distribute the reported allocation
+// evenly and mark a slot a hole only once the running allocated total is
+// exhausted; this is coarser than the Linux path but still lets callers
+// estimate a tablespace's overall compression ratio.
+func scanSparseMap(f *os.File, size int64) ([]PageFragment, error) {
+	numPages := int(size / PageSize)
+	if size%PageSize != 0 {
+		numPages++
+	}
+	fragments := make([]PageFragment, numPages)
+
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &st); err != nil {
+		// Without even st_blocks, assume every slot is fully allocated.
+		for i := range fragments {
+			fragments[i] = PageFragment{PageNo: uint32(i), FileOffset: int64(i) * PageSize, AllocatedSize: PageSize}
+		}
+		return fragments, nil
+	}
+
+	allocatedTotal := st.Blocks * 512
+	remaining := allocatedTotal
+	for i := 0; i < numPages; i++ {
+		off := int64(i) * PageSize
+		alloc := int64(PageSize)
+		if remaining <= 0 {
+			alloc = 0
+		} else if remaining < PageSize {
+			alloc = remaining
+		}
+		remaining -= alloc
+		fragments[i] = PageFragment{
+			PageNo:        uint32(i),
+			FileOffset:    off,
+			AllocatedSize: alloc,
+			IsHole:        alloc == 0,
+		}
+	}
+	return fragments, nil
+}
@@ -11,18 +11,65 @@ import (
 
 // CompactParser parses records in InnoDB compact format
 type CompactParser struct {
-	tableDef *schema.TableDef
+	tableDef           *schema.TableDef
+	overflowResolver   column.OverflowResolver
+	localLobPrefixOnly bool
+	checksumPolicy     ChecksumPolicy
+}
+
+// CompactParserOption configures a CompactParser at construction time.
+type CompactParserOption func(*CompactParser)
+
+// WithOverflowResolver makes the parser transparently follow off-page
+// BLOB/TEXT columns through resolver instead of erroring out on them.
+func WithOverflowResolver(resolver column.OverflowResolver) CompactParserOption {
+	return func(p *CompactParser) { p.overflowResolver = resolver }
+}
+
+// WithLocalLobPrefixOnly makes the parser return just the in-row local
+// prefix of an off-page column, skipping the overflow-page chain entirely.
+// Useful for callers that only need to inspect or index the prefix and want
+// to avoid the I/O of materializing the full value; it takes precedence
+// over WithOverflowResolver.
+func WithLocalLobPrefixOnly() CompactParserOption {
+	return func(p *CompactParser) { p.localLobPrefixOnly = true }
+}
+
+// WithChecksumPolicy makes the parser check pageData's whole-page checksum
+// before parsing a record, per policy. The default is ChecksumPolicySkip.
+func WithChecksumPolicy(policy ChecksumPolicy) CompactParserOption {
+	return func(p *CompactParser) { p.checksumPolicy = policy }
 }
 
 // NewCompactParser creates a new compact record parser
-func NewCompactParser(tableDef *schema.TableDef) *CompactParser {
-	return &CompactParser{
+func NewCompactParser(tableDef *schema.TableDef, opts ...CompactParserOption) *CompactParser {
+	p := &CompactParser{
 		tableDef: tableDef,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // ParseRecord parses a record from raw page data
 func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage bool) (*GenericRecord, error) {
+	return p.ParseRecordInto(pageData, recordPos, isLeafPage, &GenericRecord{})
+}
+
+// ParseRecordInto parses a record the same way ParseRecord does, but
+// populates dst instead of allocating a new GenericRecord. dst.Values is
+// reset in place (not reallocated) if already non-nil, so a caller that
+// reuses dst across many calls - PageIterator's scratch record, for
+// instance - avoids a map allocation per record.
+func (p *CompactParser) ParseRecordInto(pageData []byte, recordPos int, isLeafPage bool, dst *GenericRecord) (*GenericRecord, error) {
+	if p.checksumPolicy != ChecksumPolicySkip && !verifyPageChecksum(pageData) {
+		if p.checksumPolicy == ChecksumPolicyReject {
+			return nil, fmt.Errorf("page checksum mismatch")
+		}
+		fmt.Printf("Warning: page checksum mismatch\n")
+	}
+
 	// The actual record content starts at recordPos
 	// But we need to read backwards to get variable length headers and NULL bitmap
 
@@ -37,11 +84,17 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 		return nil, fmt.Errorf("parse record header: %w", err)
 	}
 
-	// Create the record
-	record := &GenericRecord{
-		Header:        header,
-		PrimaryKeyPos: recordPos,
-		Values:        make(map[string]interface{}),
+	// Reset and reuse the destination record
+	record := dst
+	record.Header = header
+	record.PrimaryKeyPos = recordPos
+	record.Data = nil
+	if record.Values == nil {
+		record.Values = make(map[string]interface{})
+	} else {
+		for k := range record.Values {
+			delete(record.Values, k)
+		}
 	}
 
 	// Handle special records (INFIMUM/SUPREMUM)
@@ -82,6 +135,10 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 	// Because we iterate from the last varlen column to the first, we must
 	// PREPEND each decoded length to keep varLengths in column order.
 	varLengths := make([]int, 0, len(p.tableDef.VariableLengthColumns()))
+	// externalFlags[i] mirrors varLengths[i]: true if that column's on-page
+	// bytes are a prefix followed by a BTR_EXTERN_FIELD_REF rather than the
+	// full value.
+	externalFlags := make([]bool, 0, len(p.tableDef.VariableLengthColumns()))
 	varLenHeaderSize := 0
 
 	if p.tableDef.HasVariableLengthColumn() {
@@ -119,6 +176,7 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 
 			if isNull {
 				varLengths = append([]int{0}, varLengths...) // Prepend 0 for NULL column
+				externalFlags = append([]bool{false}, externalFlags...)
 				continue
 			}
 
@@ -144,13 +202,17 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 				varLenHeaderSize++
 
 				if overflowFlag {
-					// TODO: Handle overflow pages
-					return nil, fmt.Errorf("overflow pages not yet supported")
+					// On-page bytes are a local prefix followed by a 20-byte
+					// BTR_EXTERN_FIELD_REF; parseColumnValue resolves the rest.
+					varLengths = append(varLengths, length)
+					externalFlags = append(externalFlags, true)
+					continue
 				}
 			}
 
 			// Append to maintain column order
 			varLengths = append(varLengths, length)
+			externalFlags = append(externalFlags, false)
 		}
 	}
 
@@ -183,15 +245,17 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 
 		// Get variable length if applicable
 		varLen := 0
+		external := false
 		if col.IsVariableLength() {
 			if varLenIdx < len(varLengths) {
 				varLen = varLengths[varLenIdx]
+				external = externalFlags[varLenIdx]
 				varLenIdx++
 			}
 		}
 
 		// Parse column value
-		value, bytesRead, err := column.ParseColumn(pageData, dataPos, col, varLen)
+		value, bytesRead, err := p.parseColumnValue(pageData, dataPos, col, varLen, external)
 		if err != nil {
 			return nil, fmt.Errorf("parse column %s: %w", col.Name, err)
 		}
@@ -234,15 +298,17 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 
 		// Get variable length if applicable
 		varLen := 0
+		external := false
 		if col.IsVariableLength() {
 			if varLenIdx < len(varLengths) {
 				varLen = varLengths[varLenIdx]
+				external = externalFlags[varLenIdx]
 				varLenIdx++
 			}
 		}
 
 		// Parse column value
-		value, bytesRead, err := column.ParseColumn(pageData, dataPos, col, varLen)
+		value, bytesRead, err := p.parseColumnValue(pageData, dataPos, col, varLen, external)
 		if err != nil {
 			return nil, fmt.Errorf("parse column %s: %w", col.Name, err)
 		}
@@ -267,8 +333,63 @@ func (p *CompactParser) ParseRecord(pageData []byte, recordPos int, isLeafPage b
 	return record, nil
 }
 
+// parseColumnValue parses a column's on-page bytes, transparently resolving
+// off-page BLOB/TEXT storage when external is true: varLen on-page bytes are
+// then a local prefix followed by a 20-byte BTR_EXTERN_FIELD_REF rather than
+// the full value.
+func (p *CompactParser) parseColumnValue(pageData []byte, offset int, col *schema.Column, varLen int, external bool) (interface{}, int, error) {
+	if !external {
+		return column.ParseColumn(pageData, offset, col, varLen)
+	}
+
+	const ptrSize = 20
+	if varLen < ptrSize {
+		return nil, 0, fmt.Errorf("on-page length %d too short for overflow pointer", varLen)
+	}
+	prefixLen := varLen - ptrSize
+	prefix := pageData[offset : offset+prefixLen]
+
+	ptr, err := column.ParseOverflowPointer(pageData[offset+prefixLen : offset+varLen])
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse overflow pointer: %w", err)
+	}
+	if p.localLobPrefixOnly {
+		return p.columnValueFromBytes(col, prefix), varLen, nil
+	}
+	if p.overflowResolver == nil {
+		return nil, 0, fmt.Errorf("column %s is stored off-page but no overflow resolver is configured", col.Name)
+	}
+	rest, err := p.overflowResolver.ResolveOverflow(ptr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("resolve overflow: %w", err)
+	}
+
+	full := append(append([]byte{}, prefix...), rest...)
+	return p.columnValueFromBytes(col, full), varLen, nil
+}
+
+// columnValueFromBytes converts reassembled off-page bytes to the same Go
+// type column.ParseColumn would produce for an in-row value of this column.
+func (p *CompactParser) columnValueFromBytes(col *schema.Column, data []byte) interface{} {
+	switch col.Type {
+	case schema.TypeText, schema.TypeTinyText, schema.TypeMediumText, schema.TypeLongText,
+		schema.TypeVarchar, schema.TypeChar:
+		return string(data)
+	default:
+		return append([]byte{}, data...)
+	}
+}
+
 // needsTwoByteLength checks if a variable-length column needs 2-byte length header
 func (p *CompactParser) needsTwoByteLength(col *schema.Column, firstByte int) bool {
+	return needsTwoByteVarLenHeader(col, firstByte)
+}
+
+// needsTwoByteVarLenHeader reports whether a variable-length column's
+// on-page length header is 2 bytes rather than 1, given the first header
+// byte read. Shared by CompactParser and ParseRecordProjected, since both
+// walk the same right-to-left varlen header area.
+func needsTwoByteVarLenHeader(col *schema.Column, firstByte int) bool {
 	// If length > 127, might need 2 bytes
 	// Also check if column can be long (BLOB/TEXT types or VARCHAR > 255)
 	if firstByte > 127 {
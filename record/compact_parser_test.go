@@ -0,0 +1,154 @@
+// compact_parser_test.go - CompactParser against a synthetic off-page VARCHAR
+package record
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/wilhasse/go-innodb/column"
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// antelopePrefixSize is the local prefix InnoDB keeps in-row for an
+// off-page column in Antelope/Compact row format; the rest lives in the
+// overflow chain behind a 20-byte BTR_EXTERN_FIELD_REF.
+const antelopePrefixSize = 768
+
+// fakeOverflowResolver returns a fixed byte slice regardless of the
+// pointer it's given, standing in for the overflow chain's tail - the
+// chain-walking itself is column.LobOverflowResolver's job and is covered
+// by column/lob_resolver_test.go.
+type fakeOverflowResolver struct {
+	tail []byte
+}
+
+func (r fakeOverflowResolver) ResolveOverflow(ptr column.OverflowPointer) ([]byte, error) {
+	return r.tail, nil
+}
+
+// buildOffPageRecord lays out a single compact-format leaf record with one
+// INT primary key and one VARCHAR column forced off-page: a 2-byte varlen
+// header (overflow bit set, on-page length = prefix+pointer), a 5-byte
+// record header, the PK column, a 13-byte trx_id/roll_ptr stand-in, the
+// in-row prefix, and the 20-byte overflow pointer. Returns the page bytes
+// and the position of the record's first data byte.
+func buildOffPageRecord(id int32, prefix []byte, ptr column.OverflowPointer) ([]byte, int) {
+	varLen := len(prefix) + 20
+	// 2-byte varlen header, read back-to-front by the parser: the byte
+	// closer to the record header holds 0x80 (selects the 2-byte form),
+	// 0x40 (the overflow flag), and the top 6 bits of the on-page length;
+	// the byte before it holds the low 8 bits. On-page (increasing
+	// address) order is low byte first.
+	varHeader := []byte{
+		byte(varLen & 0xFF),
+		byte(0x80 | 0x40 | (varLen>>8)&0x3F),
+	}
+
+	recHeader := make([]byte, 5) // conventional record type, no next record
+
+	// IntParser's on-page format XORs the sign bit so unsigned byte
+	// comparison sorts signed values correctly; flip it back here so the
+	// parser recovers id unchanged.
+	pk := make([]byte, 4)
+	binary.BigEndian.PutUint32(pk, uint32(id)^0x80000000)
+
+	trxAndRollPtr := make([]byte, 13)
+
+	ptrBytes := make([]byte, 20)
+	binary.BigEndian.PutUint32(ptrBytes[0:], ptr.SpaceID)
+	binary.BigEndian.PutUint32(ptrBytes[4:], ptr.PageNo)
+	binary.BigEndian.PutUint32(ptrBytes[8:], ptr.PageOff)
+	binary.BigEndian.PutUint64(ptrBytes[12:], ptr.Length)
+
+	var page []byte
+	page = append(page, varHeader...)
+	page = append(page, recHeader...)
+	recordPos := len(page)
+	page = append(page, pk...)
+	page = append(page, trxAndRollPtr...)
+	page = append(page, prefix...)
+	page = append(page, ptrBytes...)
+
+	return page, recordPos
+}
+
+func offPageTableDef(t *testing.T) *schema.TableDef {
+	t.Helper()
+	def := schema.NewTableDef("t")
+	if err := def.AddColumn(&schema.Column{Name: "id", Type: schema.TypeInt}); err != nil {
+		t.Fatalf("add id column: %v", err)
+	}
+	if err := def.AddColumn(&schema.Column{Name: "val", Type: schema.TypeVarchar, Length: 65535}); err != nil {
+		t.Fatalf("add val column: %v", err)
+	}
+	if err := def.SetPrimaryKeys([]string{"id"}); err != nil {
+		t.Fatalf("set primary keys: %v", err)
+	}
+	return def
+}
+
+func TestCompactParser_OffPageVarchar(t *testing.T) {
+	prefix := make([]byte, antelopePrefixSize)
+	for i := range prefix {
+		prefix[i] = byte('a' + i%26)
+	}
+	tail := strings.Repeat("z", 9000) // prefix + tail > 8KB total
+	want := string(prefix) + tail
+
+	ptr := column.OverflowPointer{SpaceID: 1, PageNo: 500, PageOff: 0, Length: uint64(len(tail))}
+	page, recordPos := buildOffPageRecord(42, prefix, ptr)
+
+	parser := NewCompactParser(offPageTableDef(t), WithOverflowResolver(fakeOverflowResolver{tail: []byte(tail)}))
+	rec, err := parser.ParseRecord(page, recordPos, true)
+	if err != nil {
+		t.Fatalf("ParseRecord: %v", err)
+	}
+
+	got, ok := rec.GetValue("val")
+	if !ok {
+		t.Fatal("val column missing from parsed record")
+	}
+	if got != want {
+		t.Fatalf("val mismatch: got %d bytes, want %d bytes", len(got.(string)), len(want))
+	}
+	if id, _ := rec.GetValue("id"); id != int32(42) {
+		t.Fatalf("id mismatch: got %v, want 42", id)
+	}
+}
+
+func TestCompactParser_OffPageVarchar_LocalPrefixOnly(t *testing.T) {
+	prefix := make([]byte, antelopePrefixSize)
+	for i := range prefix {
+		prefix[i] = byte('a' + i%26)
+	}
+	ptr := column.OverflowPointer{SpaceID: 1, PageNo: 500, PageOff: 0, Length: 9000}
+	page, recordPos := buildOffPageRecord(42, prefix, ptr)
+
+	// No resolver configured - WithLocalLobPrefixOnly must take precedence
+	// and never attempt to follow the overflow chain.
+	parser := NewCompactParser(offPageTableDef(t), WithLocalLobPrefixOnly())
+	rec, err := parser.ParseRecord(page, recordPos, true)
+	if err != nil {
+		t.Fatalf("ParseRecord: %v", err)
+	}
+
+	got, ok := rec.GetValue("val")
+	if !ok {
+		t.Fatal("val column missing from parsed record")
+	}
+	if got != string(prefix) {
+		t.Fatalf("val mismatch: got %d bytes, want %d bytes (prefix only)", len(got.(string)), len(prefix))
+	}
+}
+
+func TestCompactParser_OffPageVarchar_NoResolverConfigured(t *testing.T) {
+	prefix := make([]byte, antelopePrefixSize)
+	ptr := column.OverflowPointer{SpaceID: 1, PageNo: 500, Length: 9000}
+	page, recordPos := buildOffPageRecord(42, prefix, ptr)
+
+	parser := NewCompactParser(offPageTableDef(t))
+	if _, err := parser.ParseRecord(page, recordPos, true); err == nil {
+		t.Fatal("expected an error when a value is off-page but no overflow resolver is configured")
+	}
+}
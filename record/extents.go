@@ -0,0 +1,114 @@
+// extents.go - Directory-bounded record payload extents
+package record
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wilhasse/go-innodb/format"
+)
+
+// RecordExtent describes where one record's header and payload live on a
+// page: HeaderPos is the record's 5-byte compact header, DataPos is its
+// content origin (what the page directory and NextRecOffset point at), and
+// DataEnd is the first byte belonging to whatever comes next.
+type RecordExtent struct {
+	HeaderPos int
+	DataPos   int
+	DataEnd   int
+}
+
+// RecordExtentsFromData builds a bounded extent for every record reachable
+// from infimum by following NextRecOffset, using the page directory slots,
+// the heap top, and infimum/supremum as a sorted set of candidate record
+// boundaries. A record's DataEnd is the smallest candidate strictly greater
+// than its DataPos, clamped to heapTop - this replaces the old "read up to
+// 100 bytes" heuristic, which corrupted the last user record's payload.
+func RecordExtentsFromData(pageData []byte, infimum, supremum GenericRecord, dirSlots []uint16, heapTop uint16, max int) ([]RecordExtent, error) {
+	type chainEntry struct {
+		headerPos int
+		dataPos   int
+		isLast    bool
+	}
+
+	var chain []chainEntry
+	cur := infimum
+	chain = append(chain, chainEntry{headerPos: infimum.PrimaryKeyPos - format.RecordHeaderSize, dataPos: infimum.PrimaryKeyPos})
+
+	for steps := 0; steps < max; steps++ {
+		if cur.Header.NextRecOffset == 0 {
+			break
+		}
+		nextContent := cur.NextRecordPos()
+		if nextContent < format.FilHeaderSize+format.PageHeaderSize || nextContent >= format.PageSize-format.FilTrailerSize {
+			return nil, fmt.Errorf("next content position out of bounds: %d", nextContent)
+		}
+		nextHeaderPos := nextContent - format.RecordHeaderSize
+		hdr, err := ParseRecordHeader(pageData, nextHeaderPos)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, chainEntry{headerPos: nextHeaderPos, dataPos: nextContent})
+		if hdr.Type == format.RecSupremum {
+			break
+		}
+		cur = GenericRecord{PageNumber: cur.PageNumber, Header: hdr, PrimaryKeyPos: nextContent}
+	}
+
+	// Candidate boundaries: every offset the page itself claims a record
+	// starts at, from the three independent sources named in the format.
+	boundarySet := make(map[int]struct{}, len(chain)+len(dirSlots)+2)
+	for _, c := range chain {
+		boundarySet[c.dataPos] = struct{}{}
+	}
+	for _, slot := range dirSlots {
+		boundarySet[int(slot)] = struct{}{}
+	}
+	boundarySet[infimum.PrimaryKeyPos] = struct{}{}
+	boundarySet[supremum.PrimaryKeyPos] = struct{}{}
+
+	boundaries := make([]int, 0, len(boundarySet))
+	for off := range boundarySet {
+		boundaries = append(boundaries, off)
+	}
+	sort.Ints(boundaries)
+
+	extents := make([]RecordExtent, len(chain))
+	for i, c := range chain {
+		end := int(heapTop)
+		idx := sort.SearchInts(boundaries, c.dataPos+1)
+		if idx < len(boundaries) {
+			end = boundaries[idx]
+		}
+		if end > int(heapTop) {
+			end = int(heapTop)
+		}
+		extents[i] = RecordExtent{HeaderPos: c.headerPos, DataPos: c.dataPos, DataEnd: end}
+	}
+	return extents, nil
+}
+
+// WalkGarbageFromData follows the free list of deleted (but not yet
+// reclaimed) records starting at firstGarbageOff, the same way WalkRecords
+// follows the live chain: each record's header still carries a valid
+// NextRecOffset, just linking into the garbage list instead of the live one.
+func WalkGarbageFromData(pageNo uint32, pageData []byte, firstGarbageOff uint16, max int) ([]GenericRecord, error) {
+	var out []GenericRecord
+	pos := int(firstGarbageOff)
+	for steps := 0; pos != 0 && steps < max; steps++ {
+		headerPos := pos - format.RecordHeaderSize
+		if headerPos < 0 || pos >= format.PageSize-format.FilTrailerSize {
+			return out, fmt.Errorf("garbage record position out of bounds: %d", pos)
+		}
+		hdr, err := ParseRecordHeader(pageData, headerPos)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, GenericRecord{PageNumber: pageNo, Header: hdr, PrimaryKeyPos: pos})
+		if hdr.NextRecOffset == 0 {
+			break
+		}
+		pos += hdr.NextRecOffset
+	}
+	return out, nil
+}
@@ -40,7 +40,7 @@ func (r GenericRecord) SetValue(columnName string, value interface{}) {
 // String returns a string representation of the record
 func (r GenericRecord) String() string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Record(page=%d, pos=%d, type=%s", 
+	sb.WriteString(fmt.Sprintf("Record(page=%d, pos=%d, type=%d",
 		r.PageNumber, r.PrimaryKeyPos, r.Header.Type))
 	
 	if len(r.Values) > 0 {
@@ -2,71 +2,51 @@
 package record
 
 import (
-	"fmt"
 	"github.com/wilhasse/go-innodb/format"
 )
 
-// WalkRecordsFromData walks records from raw page data following the compact record header's relative next offset.
+// WalkRecordsFromData walks records from raw page data following the compact
+// record header's relative next offset. Each record's Data slice is bounded
+// using RecordExtentsFromData (page directory slots + heap walk +
+// infimum/supremum), not a fixed-size guess, so the last user record's
+// payload is no longer truncated or over-read.
 // If skipSystem is true, INFIMUM and SUPREMUM are not returned.
 // max limits the number of records to traverse (safety).
-// pageNo is the page number for reference.
-// pageData is the full 16KB page data.
-// infimum is the starting infimum record.
-func WalkRecordsFromData(pageNo uint32, pageData []byte, infimum GenericRecord, max int, skipSystem bool) ([]GenericRecord, error) {
-	var out []GenericRecord
-	cur := infimum
-	if !skipSystem {
-		out = append(out, cur)
+func WalkRecordsFromData(pageNo uint32, pageData []byte, infimum, supremum GenericRecord, dirSlots []uint16, heapTop uint16, max int, skipSystem bool) ([]GenericRecord, error) {
+	extents, err := RecordExtentsFromData(pageData, infimum, supremum, dirSlots, heapTop, max)
+	if err != nil {
+		return nil, err
 	}
-	for steps := 0; steps < max; steps++ {
-		nextContent := cur.NextRecordPos()
-		if cur.Header.NextRecOffset == 0 {
-			break // usually SUPREMUM has next==0
-		}
-		if nextContent < format.FilHeaderSize+format.PageHeaderSize || nextContent >= format.PageSize-format.FilTrailerSize {
-			return out, fmt.Errorf("next content position out of bounds: %d", nextContent)
-		}
-		nextHeaderPos := nextContent - format.RecordHeaderSize
-		if nextHeaderPos < 0 {
-			return out, fmt.Errorf("negative next header pos")
-		}
-		hdr, err := ParseRecordHeader(pageData, nextHeaderPos)
-		if err != nil {
-			return out, err
-		}
-		rec := GenericRecord{PageNumber: pageNo, Header: hdr, PrimaryKeyPos: nextContent}
 
-		// Read the actual record data
-		// For now, read up to the next record or a reasonable amount of bytes
-		dataSize := 0
-		if hdr.NextRecOffset > 0 && hdr.NextRecOffset > format.RecordHeaderSize {
-			// Size is roughly the distance to the next record minus the header
-			dataSize = hdr.NextRecOffset - format.RecordHeaderSize
-		} else if hdr.Type == format.RecSupremum {
-			// Supremum has fixed 8-byte data
-			dataSize = 8
+	var out []GenericRecord
+	for i, ext := range extents {
+		var hdr RecordHeader
+		var perr error
+		if i == 0 {
+			hdr = infimum.Header
 		} else {
-			// For the last user record or unknown cases, read a reasonable amount
-			// This is a heuristic - proper implementation needs column definitions
-			dataSize = 100 // Read up to 100 bytes of data
-			maxPos := len(pageData) - nextContent
-			if dataSize > maxPos {
-				dataSize = maxPos
+			hdr, perr = ParseRecordHeader(pageData, ext.HeaderPos)
+			if perr != nil {
+				return out, perr
 			}
 		}
 
-		if dataSize > 0 && nextContent+dataSize <= len(pageData) {
-			rec.Data = pageData[nextContent : nextContent+dataSize]
+		rec := GenericRecord{PageNumber: pageNo, Header: hdr, PrimaryKeyPos: ext.DataPos}
+		if ext.DataEnd > ext.DataPos && ext.DataEnd <= len(pageData) {
+			rec.Data = pageData[ext.DataPos:ext.DataEnd]
 		}
 
-		if rec.Header.Type == format.RecSupremum {
-			if !skipSystem {
-				out = append(out, rec)
+		isSystem := hdr.Type == format.RecInfimum || hdr.Type == format.RecSupremum
+		if isSystem && skipSystem {
+			if hdr.Type == format.RecSupremum {
+				break
 			}
-			break
+			continue
 		}
 		out = append(out, rec)
-		cur = rec
+		if hdr.Type == format.RecSupremum {
+			break
+		}
 	}
 	return out, nil
 }
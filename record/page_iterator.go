@@ -0,0 +1,130 @@
+// page_iterator.go - Streaming record iterator over one INDEX page's record
+// chain, modelled on Arrow Parquet's PageReader
+package record
+
+import (
+	"sync"
+
+	"github.com/wilhasse/go-innodb/format"
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// IterOption configures a PageIterator at construction time.
+type IterOption func(*PageIterator)
+
+// WithLeafPage tells the iterator whether pageData is a leaf (true) or
+// internal/node-pointer (false) page. Defaults to true.
+func WithLeafPage(isLeaf bool) IterOption {
+	return func(it *PageIterator) { it.isLeafPage = isLeaf }
+}
+
+// WithBufferPool makes the iterator obtain its scratch record's Values map
+// from pool (and return it there on Close) instead of allocating its own.
+// pool must vend map[string]interface{} values.
+func WithBufferPool(pool *sync.Pool) IterOption {
+	return func(it *PageIterator) { it.pool = pool }
+}
+
+// WithSkipDeleted makes Next skip over records with the deleted flag set.
+func WithSkipDeleted() IterOption {
+	return func(it *PageIterator) { it.skipDeleted = true }
+}
+
+// WithPredicate makes Next skip over records for which pred returns false.
+func WithPredicate(pred func(*GenericRecord) bool) IterOption {
+	return func(it *PageIterator) { it.predicate = pred }
+}
+
+// PageIterator streams records off one INDEX page's compact-format record
+// chain, so callers don't have to follow next_record offsets themselves. It
+// reuses a single scratch GenericRecord across calls to Next via
+// CompactParser.ParseRecordInto, so scanning a page does not allocate one
+// *GenericRecord (or one Values map, if WithBufferPool is used) per record.
+type PageIterator struct {
+	pageData    []byte
+	parser      *CompactParser
+	isLeafPage  bool
+	pool        *sync.Pool
+	skipDeleted bool
+	predicate   func(*GenericRecord) bool
+
+	pos  int
+	rec  GenericRecord
+	done bool
+	err  error
+}
+
+// NewPageIterator creates a PageIterator over pageData's record chain,
+// starting just past INFIMUM.
+func NewPageIterator(pageData []byte, tableDef *schema.TableDef, opts ...IterOption) *PageIterator {
+	it := &PageIterator{
+		pageData:   pageData,
+		parser:     NewCompactParser(tableDef),
+		isLeafPage: true,
+	}
+	for _, opt := range opts {
+		opt(it)
+	}
+	if it.pool != nil {
+		it.rec.Values = it.pool.Get().(map[string]interface{})
+	}
+
+	infimumHeaderPos := format.FilHeaderSize + format.PageHeaderSize
+	it.pos = infimumHeaderPos + format.RecordHeaderSize
+	return it
+}
+
+// Next advances to the next qualifying record and reports whether one was
+// found. It returns false at SUPREMUM or on the first error; use Err to
+// tell the two apart.
+func (it *PageIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	for {
+		rec, err := it.parser.ParseRecordInto(it.pageData, it.pos, it.isLeafPage, &it.rec)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		if rec.Header.Type == format.RecSupremum {
+			it.done = true
+			return false
+		}
+		it.pos = rec.NextRecordPos()
+
+		if it.skipDeleted && rec.Header.FlagsDeleted {
+			continue
+		}
+		if it.predicate != nil && !it.predicate(rec) {
+			continue
+		}
+		return true
+	}
+}
+
+// Record returns the record produced by the most recent call to Next that
+// returned true. The returned pointer is reused by subsequent calls to
+// Next; callers that need to retain a record past that must copy it.
+func (it *PageIterator) Record() *GenericRecord {
+	return &it.rec
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// Close returns the iterator's scratch Values map to its buffer pool, if
+// one was configured.
+func (it *PageIterator) Close() {
+	if it.pool == nil || it.rec.Values == nil {
+		return
+	}
+	for k := range it.rec.Values {
+		delete(it.rec.Values, k)
+	}
+	it.pool.Put(it.rec.Values)
+	it.rec.Values = nil
+}
@@ -0,0 +1,223 @@
+// projection.go - Column-projection API for CompactParser: skips Parse for
+// columns the caller doesn't want, walking past their bytes with Skip
+// instead.
+package record
+
+import (
+	"fmt"
+
+	"github.com/wilhasse/go-innodb/column"
+	"github.com/wilhasse/go-innodb/format"
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// RecordProjection precomputes, for one table definition and leaf/non-leaf
+// page kind, which columns ParseRecordProjected should decode versus merely
+// skip past. Build one per distinct (table, wanted columns, page kind)
+// combination and reuse it across every record that shares them; the
+// precomputed want/column slices are what let ParseRecordProjected avoid
+// any per-record lookup.
+type RecordProjection struct {
+	tableDef   *schema.TableDef
+	isLeafPage bool
+
+	pkWant    []bool // parallel to tableDef.PrimaryKeyColumns()
+	nonPKCols []*schema.Column
+	nonPKWant []bool           // parallel to nonPKCols
+	varCols   []*schema.Column // variable-length columns present in the record, in on-disk header order
+}
+
+// NewRecordProjection builds a RecordProjection over tableDef for pages of
+// the given leaf/non-leaf kind - variable-length columns present in a
+// record differ between the two, same as CompactParser.ParseRecordInto -
+// wanting only the named columns. Names not present in tableDef are
+// ignored.
+func NewRecordProjection(tableDef *schema.TableDef, isLeafPage bool, columns []string) *RecordProjection {
+	want := make(map[string]bool, len(columns))
+	for _, name := range columns {
+		want[name] = true
+	}
+
+	proj := &RecordProjection{tableDef: tableDef, isLeafPage: isLeafPage}
+
+	for _, col := range tableDef.PrimaryKeyColumns() {
+		proj.pkWant = append(proj.pkWant, want[col.Name])
+	}
+	for _, col := range tableDef.Columns {
+		if col.IsPrimaryKey {
+			continue
+		}
+		proj.nonPKCols = append(proj.nonPKCols, col)
+		proj.nonPKWant = append(proj.nonPKWant, want[col.Name])
+	}
+
+	if isLeafPage {
+		proj.varCols = tableDef.VariableLengthColumns()
+	} else {
+		proj.varCols = tableDef.GetPrimaryKeyVarLenColumns()
+	}
+
+	return proj
+}
+
+// ParseRecordProjected parses the record at recordPos in pageData the same
+// way CompactParser.ParseRecordInto does - reading the same NULL bitmap and
+// variable-length header area - except it calls column.SkipColumn instead
+// of column.ParseColumn for any column outside proj's wanted set, so the
+// allocations a full parse would pay for an unwanted DATETIME/VARCHAR/BLOB
+// never happen. It returns the decoded record and the offset in pageData
+// immediately following its last column.
+//
+// Off-page BLOB/TEXT columns are not resolved; a wanted column stored
+// off-page comes back as its in-row prefix and pointer bytes, same as
+// CompactParser with no overflow resolver configured would report an
+// error for - callers needing full LOB resolution should use
+// CompactParser instead.
+func ParseRecordProjected(pageData []byte, recordPos int, proj *RecordProjection) (GenericRecord, int, error) {
+	var rec GenericRecord
+
+	headerPos := recordPos - format.RecordHeaderSize
+	if headerPos < 0 {
+		return rec, 0, fmt.Errorf("invalid record position")
+	}
+
+	header, err := ParseRecordHeader(pageData, headerPos)
+	if err != nil {
+		return rec, 0, fmt.Errorf("parse record header: %w", err)
+	}
+
+	rec.Header = header
+	rec.PrimaryKeyPos = recordPos
+	rec.Values = make(map[string]interface{})
+
+	if header.Type == format.RecInfimum || header.Type == format.RecSupremum {
+		endPos := recordPos + format.SystemRecordBytes
+		rec.Data = pageData[recordPos:endPos]
+		return rec, endPos, nil
+	}
+
+	tableDef := proj.tableDef
+
+	nullBitmap := make([]bool, tableDef.NullableColumnCount())
+	nullBitmapSize := 0
+	if proj.isLeafPage && tableDef.HasNullableColumn() {
+		nullBitmapSize = tableDef.NullBitmapSize()
+		nullBitmapPos := headerPos - nullBitmapSize
+		if nullBitmapPos < 0 {
+			return rec, 0, fmt.Errorf("invalid NULL bitmap position")
+		}
+		nullBytes := pageData[nullBitmapPos:headerPos]
+		for idx := range tableDef.NullableColumns() {
+			byteIdx := idx / 8
+			bitIdx := idx % 8
+			if byteIdx < len(nullBytes) {
+				nullBitmap[idx] = (nullBytes[byteIdx] & (1 << bitIdx)) != 0
+			}
+		}
+	}
+
+	isColNull := func(col *schema.Column) bool {
+		if !col.Nullable {
+			return false
+		}
+		for idx, nullCol := range tableDef.NullableColumns() {
+			if nullCol.Name == col.Name {
+				return nullBitmap[idx]
+			}
+		}
+		return false
+	}
+
+	varLengths := make([]int, 0, len(proj.varCols))
+	if tableDef.HasVariableLengthColumn() {
+		varHeaderPos := headerPos - nullBitmapSize
+		for _, col := range proj.varCols {
+			if isColNull(col) {
+				varLengths = append([]int{0}, varLengths...)
+				continue
+			}
+
+			varHeaderPos--
+			if varHeaderPos < 0 {
+				return rec, 0, fmt.Errorf("invalid variable header position")
+			}
+			length := int(pageData[varHeaderPos])
+
+			if needsTwoByteVarLenHeader(col, length) {
+				varHeaderPos--
+				if varHeaderPos < 0 {
+					return rec, 0, fmt.Errorf("invalid variable header position")
+				}
+				length = ((length & 0x3F) << 8) | int(pageData[varHeaderPos])
+			}
+			varLengths = append(varLengths, length)
+		}
+	}
+
+	dataPos := recordPos
+	varLenIdx := 0
+
+	project := func(col *schema.Column, want bool) error {
+		if isColNull(col) {
+			if want {
+				rec.Values[col.Name] = nil
+			}
+			if col.IsVariableLength() {
+				varLenIdx++
+			}
+			return nil
+		}
+
+		varLen := 0
+		if col.IsVariableLength() && varLenIdx < len(varLengths) {
+			varLen = varLengths[varLenIdx]
+			varLenIdx++
+		}
+
+		if want {
+			value, bytesRead, err := column.ParseColumn(pageData, dataPos, col, varLen)
+			if err != nil {
+				return fmt.Errorf("parse column %s: %w", col.Name, err)
+			}
+			rec.Values[col.Name] = value
+			dataPos += bytesRead
+			return nil
+		}
+
+		bytesRead, err := column.SkipColumn(pageData, dataPos, col, varLen)
+		if err != nil {
+			return fmt.Errorf("skip column %s: %w", col.Name, err)
+		}
+		dataPos += bytesRead
+		return nil
+	}
+
+	for i, col := range tableDef.PrimaryKeyColumns() {
+		if err := project(col, proj.pkWant[i]); err != nil {
+			return rec, 0, err
+		}
+	}
+
+	if proj.isLeafPage {
+		dataPos += 13 // 6-byte transaction ID + 7-byte roll pointer
+	}
+
+	for i, col := range proj.nonPKCols {
+		if err := project(col, proj.nonPKWant[i]); err != nil {
+			return rec, 0, err
+		}
+	}
+
+	endPos := recordPos + header.NextRecOffset
+	if header.NextRecOffset <= 0 || endPos > len(pageData) {
+		endPos = dataPos
+		if endPos-recordPos > 100 {
+			endPos = recordPos + 100
+		}
+	}
+	if endPos > recordPos && endPos <= len(pageData) {
+		rec.Data = pageData[recordPos:endPos]
+	}
+
+	return rec, dataPos, nil
+}
@@ -0,0 +1,199 @@
+// redundant.go - Parser for InnoDB's original REDUNDANT record format
+package record
+
+import (
+	"fmt"
+
+	"github.com/wilhasse/go-innodb/format"
+)
+
+// RedundantHeaderSize is REDUNDANT's record header width, 6 bytes versus
+// COMPACT's 5.
+const RedundantHeaderSize = 6
+
+// RedundantRecordHeader is REDUNDANT's 6-byte record header. Unlike
+// COMPACT, REDUNDANT encodes the next-record pointer as an absolute page
+// offset rather than a delta from the current record's content start -
+// callers walking a REDUNDANT page must dereference it directly instead of
+// adding it to PrimaryKeyPos.
+type RedundantRecordHeader struct {
+	FlagsMinRec   bool
+	FlagsDeleted  bool
+	NumOwned      uint8
+	HeapNumber    uint16
+	NumFields     uint16
+	ShortFields   bool // true: 1-byte field-offset entries, false: 2-byte
+	NextRecOffset int  // absolute page offset of the next record's content, 0 = none
+}
+
+func ParseRedundantRecordHeader(p []byte, off int) (RedundantRecordHeader, error) {
+	if off < 0 || off+RedundantHeaderSize > len(p) {
+		return RedundantRecordHeader{}, fmt.Errorf("short redundant record header")
+	}
+	b0 := p[off]
+	flags := (b0 & 0xF0) >> 4
+	nOwned := b0 & 0x0F
+
+	// bytes off+1..off+3 pack shortFields(1 bit) | n_fields(10 bits) | heap_no(13 bits)
+	packed := uint32(p[off+1])<<16 | uint32(p[off+2])<<8 | uint32(p[off+3])
+	shortFields := packed&0x800000 != 0
+	nFields := uint16((packed >> 13) & 0x3FF)
+	heapNo := uint16(packed & 0x1FFF)
+
+	next, err := format.Be16(p, off+4)
+	if err != nil {
+		return RedundantRecordHeader{}, err
+	}
+
+	return RedundantRecordHeader{
+		FlagsMinRec:   flags&0x1 != 0,
+		FlagsDeleted:  flags&0x2 != 0,
+		NumOwned:      nOwned,
+		HeapNumber:    heapNo,
+		NumFields:     nFields,
+		ShortFields:   shortFields,
+		NextRecOffset: int(next),
+	}, nil
+}
+
+// RedundantRecord is a parsed REDUNDANT-format record together with its
+// field-offset array, so callers can slice individual column values without
+// needing a schema.
+type RedundantRecord struct {
+	PageNumber    uint32
+	Header        RedundantRecordHeader
+	PrimaryKeyPos int // absolute offset where the record's content starts
+	Data          []byte
+
+	fieldEnds   []int // cumulative end-offset of each field (absolute), column order
+	fieldNull   []bool
+	fieldExtern []bool
+}
+
+// FieldOffsets returns the absolute end offset of each field, in column
+// order. Field i's bytes span FieldOffsets()[i-1] (or PrimaryKeyPos for
+// i==0) to FieldOffsets()[i].
+func (r RedundantRecord) FieldOffsets() []int { return r.fieldEnds }
+
+// FieldIsNull reports whether field i was flagged SQL NULL in the offset array.
+func (r RedundantRecord) FieldIsNull(i int) bool {
+	if i < 0 || i >= len(r.fieldNull) {
+		return false
+	}
+	return r.fieldNull[i]
+}
+
+// FieldIsExtern reports whether field i was flagged as stored off-page (the
+// 2-byte field-offset form's bit 15). Only the 2-byte form carries this bit;
+// a record whose fields all use the 1-byte form never has an extern field.
+func (r RedundantRecord) FieldIsExtern(i int) bool {
+	if i < 0 || i >= len(r.fieldExtern) {
+		return false
+	}
+	return r.fieldExtern[i]
+}
+
+// ParseRedundantRecord parses a REDUNDANT record whose header starts at
+// headerPos (so content starts at headerPos+RedundantHeaderSize), reading
+// its field-offset array immediately before the header.
+func ParseRedundantRecord(pageNo uint32, pageData []byte, headerPos int) (RedundantRecord, error) {
+	hdr, err := ParseRedundantRecordHeader(pageData, headerPos)
+	if err != nil {
+		return RedundantRecord{}, err
+	}
+	contentPos := headerPos + RedundantHeaderSize
+
+	entryWidth := 2
+	if hdr.ShortFields {
+		entryWidth = 1
+	}
+	arrayStart := headerPos - entryWidth*int(hdr.NumFields)
+	if arrayStart < 0 {
+		return RedundantRecord{}, fmt.Errorf("field offset array out of bounds")
+	}
+
+	ends := make([]int, hdr.NumFields)
+	nulls := make([]bool, hdr.NumFields)
+	externs := make([]bool, hdr.NumFields)
+	// Entries are stored in reverse column order, immediately before the header.
+	for i := 0; i < int(hdr.NumFields); i++ {
+		entryPos := headerPos - (i+1)*entryWidth
+		fieldIdx := int(hdr.NumFields) - 1 - i
+		if hdr.ShortFields {
+			b := pageData[entryPos]
+			nulls[fieldIdx] = b&0x80 != 0
+			ends[fieldIdx] = contentPos + int(b&0x7F)
+		} else {
+			v, err := format.Be16(pageData, entryPos)
+			if err != nil {
+				return RedundantRecord{}, err
+			}
+			nulls[fieldIdx] = v&0x4000 != 0
+			externs[fieldIdx] = v&0x8000 != 0
+			ends[fieldIdx] = contentPos + int(v&0x3FFF)
+		}
+	}
+
+	rec := RedundantRecord{PageNumber: pageNo, Header: hdr, PrimaryKeyPos: contentPos, fieldEnds: ends, fieldNull: nulls, fieldExtern: externs}
+	if hdr.NumFields > 0 {
+		dataEnd := ends[len(ends)-1]
+		if dataEnd > contentPos && dataEnd <= len(pageData) {
+			rec.Data = pageData[contentPos:dataEnd]
+		}
+	}
+	return rec, nil
+}
+
+// ToGenericRecord folds a RedundantRecord into the same GenericRecord shape
+// COMPACT parsing produces, rewriting the absolute NextRecOffset into the
+// relative delta GenericRecord.NextRecordPos expects, so a format-dispatched
+// walker can hand callers a single unified record stream regardless of the
+// page's underlying row format.
+func (r RedundantRecord) ToGenericRecord(recType format.RecordType) GenericRecord {
+	return GenericRecord{
+		PageNumber:    r.PageNumber,
+		PrimaryKeyPos: r.PrimaryKeyPos,
+		Data:          r.Data,
+		Header: RecordHeader{
+			FlagsMinRec:   r.Header.FlagsMinRec,
+			FlagsDeleted:  r.Header.FlagsDeleted,
+			NumOwned:      r.Header.NumOwned,
+			HeapNumber:    r.Header.HeapNumber,
+			Type:          recType,
+			NextRecOffset: r.Header.NextRecOffset - r.PrimaryKeyPos,
+		},
+	}
+}
+
+// WalkRedundantRecordsFromData walks a REDUNDANT-format page starting at
+// infimumPos, dereferencing each record's absolute NextRecOffset, and
+// returns the same GenericRecord stream COMPACT pages produce via
+// WalkRecordsFromData.
+func WalkRedundantRecordsFromData(pageNo uint32, pageData []byte, infimumPos, supremumPos int, max int, skipSystem bool) ([]GenericRecord, error) {
+	var out []GenericRecord
+	pos := infimumPos
+	for steps := 0; steps < max; steps++ {
+		rec, err := ParseRedundantRecord(pageNo, pageData, pos-RedundantHeaderSize)
+		if err != nil {
+			return out, err
+		}
+
+		recType := format.RecConventional
+		if pos == infimumPos {
+			recType = format.RecInfimum
+		} else if pos == supremumPos {
+			recType = format.RecSupremum
+		}
+
+		generic := rec.ToGenericRecord(recType)
+		isSystem := recType == format.RecInfimum || recType == format.RecSupremum
+		if !(isSystem && skipSystem) {
+			out = append(out, generic)
+		}
+		if recType == format.RecSupremum || rec.Header.NextRecOffset == 0 {
+			break
+		}
+		pos = rec.Header.NextRecOffset
+	}
+	return out, nil
+}
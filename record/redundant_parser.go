@@ -0,0 +1,81 @@
+// redundant_parser.go - Schema-aware parser for REDUNDANT-format records
+package record
+
+import (
+	"fmt"
+
+	"github.com/wilhasse/go-innodb/column"
+	"github.com/wilhasse/go-innodb/format"
+	"github.com/wilhasse/go-innodb/schema"
+)
+
+// RedundantParser parses records in InnoDB's original REDUNDANT row format,
+// playing the same role CompactParser plays for COMPACT pages. Unlike
+// COMPACT, a REDUNDANT record's field-offset array already bounds every
+// column (ParseRedundantRecord has read it), so there's no NULL-bitmap or
+// variable-length header decoding to do here.
+type RedundantParser struct {
+	tableDef *schema.TableDef
+}
+
+// NewRedundantParser creates a new REDUNDANT record parser.
+func NewRedundantParser(tableDef *schema.TableDef) *RedundantParser {
+	return &RedundantParser{tableDef: tableDef}
+}
+
+// ParseRecord parses the REDUNDANT record whose header starts at headerPos
+// into column values.
+func (p *RedundantParser) ParseRecord(pageNo uint32, pageData []byte, headerPos int, isLeafPage bool) (*GenericRecord, error) {
+	rr, err := ParseRedundantRecord(pageNo, pageData, headerPos)
+	if err != nil {
+		return nil, fmt.Errorf("parse redundant record: %w", err)
+	}
+
+	generic := rr.ToGenericRecord(format.RecConventional)
+	generic.Values = make(map[string]interface{})
+
+	cols := p.orderedColumns(isLeafPage)
+	offsets := rr.FieldOffsets()
+	if len(cols) > len(offsets) {
+		return nil, fmt.Errorf("schema expects %d fields, record has %d", len(cols), len(offsets))
+	}
+
+	pos := rr.PrimaryKeyPos
+	for i, col := range cols {
+		end := offsets[i]
+		if rr.FieldIsNull(i) {
+			generic.Values[col.Name] = nil
+			pos = end
+			continue
+		}
+
+		value, _, err := column.ParseColumn(pageData, pos, col, end-pos)
+		if err != nil {
+			return nil, fmt.Errorf("parse column %s: %w", col.Name, err)
+		}
+		generic.Values[col.Name] = value
+		pos = end
+	}
+
+	return &generic, nil
+}
+
+// orderedColumns returns the columns a record carries, in on-disk order:
+// primary key columns first, then (for leaf records) the remaining table
+// columns - the same ordering CompactParser assumes. Internal (node
+// pointer) records only carry the primary key columns.
+func (p *RedundantParser) orderedColumns(isLeafPage bool) []*schema.Column {
+	pkCols := p.tableDef.PrimaryKeyColumns()
+	if !isLeafPage {
+		return pkCols
+	}
+
+	cols := make([]*schema.Column, 0, len(p.tableDef.Columns))
+	cols = append(cols, pkCols...)
+	for _, col := range p.tableDef.Columns {
+		if !col.IsPrimaryKey {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
@@ -0,0 +1,88 @@
+// recordformat.go - Pluggable record-output formats, mirroring bbolt's
+// page-item --format modes (auto, hex, ascii-encoded, redacted)
+package goinnodb
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RecordFormat selects how a record's raw Data is rendered for display.
+type RecordFormat string
+
+const (
+	RecordFormatAuto         RecordFormat = "auto"
+	RecordFormatHex          RecordFormat = "hex"
+	RecordFormatAsciiEncoded RecordFormat = "ascii-encoded"
+	RecordFormatRedacted     RecordFormat = "redacted"
+)
+
+// RecordFormatter renders a record's raw Data as a display string.
+type RecordFormatter interface {
+	Format(data []byte) string
+}
+
+// NewRecordFormatter returns the formatter for the named mode. An empty
+// mode is treated as RecordFormatAuto.
+func NewRecordFormatter(mode RecordFormat) (RecordFormatter, error) {
+	switch mode {
+	case RecordFormatAuto, "":
+		return autoFormatter{}, nil
+	case RecordFormatHex:
+		return hexFormatter{}, nil
+	case RecordFormatAsciiEncoded:
+		return asciiEncodedFormatter{}, nil
+	case RecordFormatRedacted:
+		return redactedFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown record format: %q", mode)
+	}
+}
+
+// hexFormatter renders the full byte slice as lowercase hex.
+type hexFormatter struct{}
+
+func (hexFormatter) Format(data []byte) string { return hex.EncodeToString(data) }
+
+// asciiEncodedFormatter passes printable ASCII through unescaped and
+// renders everything else as a \xNN escape, the same "readable but
+// lossless" tradeoff bbolt's ascii-encoded mode makes.
+type asciiEncodedFormatter struct{}
+
+func (asciiEncodedFormatter) Format(data []byte) string {
+	var b strings.Builder
+	for _, c := range data {
+		if c >= 32 && c <= 126 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "\\x%02x", c)
+		}
+	}
+	return b.String()
+}
+
+// redactedFormatter hides the value entirely, keeping only its length -
+// for dumping pages that may hold sensitive column data.
+type redactedFormatter struct{}
+
+func (redactedFormatter) Format(data []byte) string {
+	return fmt.Sprintf("<redacted %d bytes>", len(data))
+}
+
+// autoFormatter picks ascii-encoded or hex per record depending on whether
+// the data looks mostly printable.
+type autoFormatter struct{}
+
+func (autoFormatter) Format(data []byte) string {
+	printable := 0
+	for _, c := range data {
+		if c >= 32 && c <= 126 {
+			printable++
+		}
+	}
+	if len(data) > 0 && printable*2 >= len(data) {
+		return asciiEncodedFormatter{}.Format(data)
+	}
+	return hexFormatter{}.Format(data)
+}
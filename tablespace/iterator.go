@@ -0,0 +1,117 @@
+// iterator.go - Pull-style record iteration over a tablespace's B+tree
+package tablespace
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/wilhasse/go-innodb"
+	"github.com/wilhasse/go-innodb/pagesource"
+)
+
+// maxRecordsPerPage bounds how many records Iterator collects per page, the
+// same knob IndexPage.WalkRecords exposes directly.
+const maxRecordsPerPage = 10000
+
+// Iterator walks a tablespace's index B-tree leaf level in key order,
+// yielding one record.GenericRecord at a time through Next. It's built on
+// pagesource.Storage so callers can point it at a real .ibd file, a
+// BufferStorage fixture, or anything else implementing the interface.
+type Iterator struct {
+	storage pagesource.Storage
+	spaceID uint32
+
+	page    *goinnodb.IndexPage
+	records []goinnodb.GenericRecord
+	pos     int
+	done    bool
+}
+
+// NewIterator opens an Iterator positioned at the leftmost leaf reachable
+// from rootPageNo, ready to scan every record in key order via Next.
+func NewIterator(storage pagesource.Storage, spaceID, rootPageNo uint32) (*Iterator, error) {
+	it := &Iterator{storage: storage, spaceID: spaceID}
+	if err := it.descendTo(rootPageNo, func([]goinnodb.GenericRecord) int { return 0 }); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// Seek descends from rootPageNo to a leaf page, using pick to choose which
+// child to follow at each internal level: pick is given that level's
+// non-system records and returns the index of the one whose subtree to
+// descend into. This is the hook range seeks use to land on the leaf
+// holding (or immediately after) a search key, without Iterator itself
+// needing to know how to compare keys.
+func (it *Iterator) Seek(rootPageNo uint32, pick func(records []goinnodb.GenericRecord) int) error {
+	return it.descendTo(rootPageNo, pick)
+}
+
+func (it *Iterator) descendTo(pageNo uint32, pick func([]goinnodb.GenericRecord) int) error {
+	for {
+		page, records, err := it.loadPage(pageNo)
+		if err != nil {
+			return err
+		}
+		if page.IsLeaf() {
+			it.page, it.records, it.pos, it.done = page, records, 0, false
+			return nil
+		}
+
+		i := pick(records)
+		if i < 0 || i >= len(records) {
+			return fmt.Errorf("pick returned out-of-range index %d for %d records", i, len(records))
+		}
+		child := records[i].ChildPageNumber
+		if child == 0 {
+			return fmt.Errorf("page %d: record %d has no child page pointer", pageNo, i)
+		}
+		pageNo = child
+	}
+}
+
+func (it *Iterator) loadPage(pageNo uint32) (*goinnodb.IndexPage, []goinnodb.GenericRecord, error) {
+	raw, err := it.storage.ReadPage(it.spaceID, pageNo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read page %d: %w", pageNo, err)
+	}
+	inner, err := goinnodb.NewInnerPage(pageNo, raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse page %d: %w", pageNo, err)
+	}
+	page, err := goinnodb.ParseIndexPage(inner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse index page %d: %w", pageNo, err)
+	}
+	records, err := page.WalkRecords(maxRecordsPerPage, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("walk records on page %d: %w", pageNo, err)
+	}
+	return page, records, nil
+}
+
+// Next returns the next record in key order, following FIL.Next across leaf
+// pages as each one is exhausted. It returns io.EOF once the leaf chain ends.
+func (it *Iterator) Next() (*goinnodb.GenericRecord, error) {
+	for {
+		if it.done {
+			return nil, io.EOF
+		}
+		if it.pos < len(it.records) {
+			rec := it.records[it.pos]
+			it.pos++
+			return &rec, nil
+		}
+
+		next := it.page.Inner.FIL.Next
+		if next == nil {
+			it.done = true
+			return nil, io.EOF
+		}
+		page, records, err := it.loadPage(*next)
+		if err != nil {
+			return nil, err
+		}
+		it.page, it.records, it.pos = page, records, 0
+	}
+}
@@ -0,0 +1,132 @@
+// tree.go - B+tree traversal across INDEX pages
+package goinnodb
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TreeNode is one page visited while walking a B+tree from its root. Leaf
+// nodes carry no children; internal nodes carry one child per node-pointer
+// record, in the same order WalkRecords returned them.
+type TreeNode struct {
+	PageNo   uint32
+	Level    uint16
+	Leaf     bool
+	Records  []GenericRecord
+	Children []*TreeNode
+}
+
+// maxTreeDepth bounds how many levels WalkTree will descend. InnoDB B+trees
+// are never anywhere near this deep in practice; the limit exists purely to
+// turn a corrupt .ibd file that points back at an ancestor into an error
+// instead of a stack overflow.
+const maxTreeDepth = 64
+
+// WalkTree walks the B+tree rooted at rootPageNo, descending through every
+// non-leaf level via each record's ChildPageNumber until it reaches leaf
+// pages. maxRecords bounds how many records WalkRecords collects per page.
+//
+// A corrupt .ibd file can have a node-pointer record point back at a page
+// already on the current path, or at a page visited via another branch, so
+// WalkTree tracks every page number it has descended into on the current
+// path and rejects a repeat with an error rather than recursing forever; it
+// also bails out past maxTreeDepth as a backstop against very deep (but
+// non-cyclic) corruption.
+func WalkTree(pr PageFetcher, rootPageNo uint32, maxRecords int) (*TreeNode, error) {
+	visited := map[uint32]bool{}
+	return walkTree(pr, rootPageNo, maxRecords, visited, 0)
+}
+
+func walkTree(pr PageFetcher, pageNo uint32, maxRecords int, visited map[uint32]bool, depth int) (*TreeNode, error) {
+	if depth > maxTreeDepth {
+		return nil, fmt.Errorf("page %d: tree depth exceeds %d, possible corruption", pageNo, maxTreeDepth)
+	}
+	if visited[pageNo] {
+		return nil, fmt.Errorf("page %d: already visited on this path, possible cycle", pageNo)
+	}
+	visited[pageNo] = true
+	defer delete(visited, pageNo)
+
+	ip, err := pr.ReadPage(pageNo)
+	if err != nil {
+		return nil, fmt.Errorf("read page %d: %w", pageNo, err)
+	}
+	idx, err := ParseIndexPage(ip)
+	if err != nil {
+		return nil, fmt.Errorf("parse index page %d: %w", pageNo, err)
+	}
+
+	recs, err := idx.WalkRecords(maxRecords, true)
+	if err != nil {
+		return nil, fmt.Errorf("walk records on page %d: %w", pageNo, err)
+	}
+
+	node := &TreeNode{PageNo: pageNo, Level: idx.Hdr.PageLevel, Leaf: idx.IsLeaf(), Records: recs}
+	if node.Leaf {
+		return node, nil
+	}
+
+	for _, rec := range recs {
+		if rec.ChildPageNumber == 0 {
+			return nil, fmt.Errorf("page %d: record at %d has no child page pointer", pageNo, rec.PrimaryKeyPos)
+		}
+		child, err := walkTree(pr, rec.ChildPageNumber, maxRecords, visited, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// TreeStats summarizes a walked tree: how many pages it has at each role,
+// how many user records it holds in total, and how deep it goes.
+type TreeStats struct {
+	InternalNodes int
+	LeafNodes     int
+	TotalRecords  int
+	MaxDepth      int
+}
+
+// ComputeTreeStats walks node and its descendants, computed by WalkTree, and
+// totals them into a TreeStats.
+func ComputeTreeStats(node *TreeNode) TreeStats {
+	var stats TreeStats
+	computeTreeStats(node, 0, &stats)
+	return stats
+}
+
+func computeTreeStats(node *TreeNode, depth int, stats *TreeStats) {
+	if node.Leaf {
+		stats.LeafNodes++
+	} else {
+		stats.InternalNodes++
+	}
+	stats.TotalRecords += len(node.Records)
+	if depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+	for _, child := range node.Children {
+		computeTreeStats(child, depth+1, stats)
+	}
+}
+
+// PrintTree writes an indented, human-readable dump of a walked tree to w,
+// one line per page showing its level, leaf/internal status, and record count.
+func PrintTree(w io.Writer, node *TreeNode) {
+	printTree(w, node, 0)
+}
+
+func printTree(w io.Writer, node *TreeNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	kind := "internal"
+	if node.Leaf {
+		kind = "leaf"
+	}
+	fmt.Fprintf(w, "%spage=%d level=%d (%s) records=%d\n", indent, node.PageNo, node.Level, kind, len(node.Records))
+	for _, child := range node.Children {
+		printTree(w, child, depth+1)
+	}
+}
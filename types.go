@@ -19,10 +19,24 @@ const (
 type PageType uint16
 
 const (
-	PageTypeAllocated PageType = 0
-	PageTypeIndex     PageType = 17855
-	PageTypeUndoLog   PageType = 2
-	PageTypeSDI       PageType = 17853
+	PageTypeAllocated           PageType = 0
+	PageTypeIndex               PageType = 17855
+	PageTypeUndoLog             PageType = 2
+	PageTypeSDI                 PageType = 17853
+	PageTypeCompressed          PageType = 34354
+	PageTypeCompressedEncrypted PageType = 37401
+	PageTypeEncrypted           PageType = 37402
+)
+
+// CompressionAlgo identifies the codec used by MySQL's transparent page
+// compression (FIL_PAGE_COMPRESSED), stored as a single byte right after
+// the compressed payload's length in the page compression header.
+type CompressionAlgo uint8
+
+const (
+	CompressionNone CompressionAlgo = 0
+	CompressionZlib CompressionAlgo = 1
+	CompressionLZ4  CompressionAlgo = 2
 )
 
 type PageFormat uint8
@@ -0,0 +1,123 @@
+// zstd_decompress.go - Zstandard page decompression
+//
+// Covers both of this module's zstd use sites: MariaDB's PAGE_COMPRESSED
+// with PAGE_COMPRESSION_ALGORITHM=zstd (algorithm id 7, dispatched from
+// mariadb_compressed.go's DecompressMariaDBPage) and a zstd-framed variant
+// of the Oracle-style ROW_FORMAT=COMPRESSED physical page, for builds
+// configured to use it instead of zlib.
+package goinnodb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic is the 4-byte little-endian magic number every zstd frame
+// starts with.
+const zstdMagic = 0xFD2FB528
+
+// zstdDecoderPool hands out *zstd.Decoder instances so repeated page
+// decompression doesn't pay for a fresh decoder (and its internal buffers)
+// on every call.
+var zstdDecoderPool = sync.Pool{
+	New: func() interface{} {
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			// Only fails on invalid options; NewReader(nil) with no
+			// options never does.
+			panic(fmt.Sprintf("zstd: failed to create decoder: %v", err))
+		}
+		return dec
+	},
+}
+
+// decompressZstdFrame inflates a zstd frame at the start of src, erroring
+// if the decoded size doesn't match wantLen exactly.
+func decompressZstdFrame(src []byte, wantLen int) ([]byte, error) {
+	if len(src) < 4 || binary.LittleEndian.Uint32(src[:4]) != zstdMagic {
+		return nil, fmt.Errorf("zstd: bad frame magic")
+	}
+
+	dec := zstdDecoderPool.Get().(*zstd.Decoder)
+	defer zstdDecoderPool.Put(dec)
+
+	dst := make([]byte, 0, wantLen)
+	dst, err := dec.DecodeAll(src, dst)
+	if err != nil {
+		return nil, fmt.Errorf("zstd: %w", err)
+	}
+	if len(dst) != wantLen {
+		return nil, fmt.Errorf("zstd: frame decoded to %d bytes, expected %d", len(dst), wantLen)
+	}
+	return dst, nil
+}
+
+// decompressZstdPayload inflates a MariaDB PAGE_COMPRESSED payload (the
+// bytes following the FIL header) into plainLen bytes, for
+// DecompressMariaDBPage's zstd case.
+func decompressZstdPayload(payload []byte, plainLen int) ([]byte, error) {
+	return decompressZstdFrame(payload, plainLen)
+}
+
+// DecompressPageZstd decompresses a zstd-framed ROW_FORMAT=COMPRESSED page,
+// mirroring DecompressPage's zlib-based algorithm but for the zstd variant:
+// the FIL header and page header are stored uncompressed, and the zstd
+// frame occupies the rest of the physical page up to the mod-log trailer.
+func DecompressPageZstd(src []byte, physical int) ([]byte, error) {
+	validSize := false
+	for _, size := range CompressedPageSizes {
+		if physical == size {
+			validSize = true
+			break
+		}
+	}
+	if !validSize {
+		return nil, fmt.Errorf("invalid physical page size: %d", physical)
+	}
+	if len(src) < physical {
+		return nil, fmt.Errorf("source data too small: %d < %d", len(src), physical)
+	}
+
+	dst := make([]byte, LogicalPageSize)
+
+	pageType, err := be16(src, 24)
+	if err != nil {
+		return nil, fmt.Errorf("read page type: %w", err)
+	}
+	if PageType(pageType) != PageTypeIndex {
+		copy(dst, src[:physical])
+		return dst, nil
+	}
+
+	headerEnd := FilHeaderSize + PageHeaderSize
+	if headerEnd > physical {
+		return nil, fmt.Errorf("physical page too small for index header: %d", physical)
+	}
+	copy(dst[:headerEnd], src[:headerEnd])
+
+	modLogLenOff := physical - 2
+	modLogLen, err := be16(src, modLogLenOff)
+	if err != nil {
+		return nil, fmt.Errorf("read mod log length: %w", err)
+	}
+	compressedEnd := physical - 2 - int(modLogLen)
+	if compressedEnd < headerEnd || compressedEnd > physical-2 {
+		return nil, fmt.Errorf("invalid mod log length: %d", modLogLen)
+	}
+
+	plain, err := decompressZstdFrame(src[headerEnd:compressedEnd], LogicalPageSize-headerEnd)
+	if err != nil {
+		return nil, err
+	}
+	copy(dst[headerEnd:], plain)
+
+	if err := applyModLog(dst, src[compressedEnd:physical-2]); err != nil {
+		return nil, fmt.Errorf("apply mod log: %w", err)
+	}
+
+	restampFilTrailer(dst)
+	return dst, nil
+}